@@ -0,0 +1,146 @@
+package armid
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ancestorOf returns the immediate ancestor of the resource id, or nil for the tenant.
+// It steps to the Parent() within the same provider (or root scope) first, then to the ParentScope().
+// The provider level resource ids (e.g. /subscriptions/0000/providers/Microsoft.Foo) are not regarded as an ancestor.
+// The root scopes are contained in each other, i.e. resource group -> subscription -> tenant, management group -> tenant.
+func ancestorOf(id ResourceId) ResourceId {
+	switch id := id.(type) {
+	case *TenantId:
+		return nil
+	case *SubscriptionId:
+		if p := id.Parent(); p != nil {
+			return p
+		}
+		return &TenantId{}
+	case *ResourceGroup:
+		if p := id.Parent(); p != nil {
+			return p
+		}
+		return &SubscriptionId{
+			Id:                           id.SubscriptionId,
+			subscriptionsLiteralOverride: id.subscriptionsLiteralOverride,
+		}
+	case *ManagementGroup:
+		if p := id.Parent(); p != nil {
+			return p
+		}
+		return &TenantId{}
+	case *ScopedResourceId:
+		if len(id.AttrTypes) > 1 {
+			return id.Parent()
+		}
+		return id.AttrParentScope
+	}
+	return nil
+}
+
+// Ancestors returns the ancestors of the resource id, from the immediate parent up to the tenant.
+// The walk crosses both the Parent() and the ParentScope() boundaries, e.g. for
+// /subscriptions/0000/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet1, it returns:
+//
+//   - /subscriptions/0000/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1
+//   - /subscriptions/0000/resourceGroups/rg1
+//   - /subscriptions/0000
+//   - /
+//
+// The returned resource ids don't alias the input.
+func Ancestors(id ResourceId) []ResourceId {
+	var out []ResourceId
+	for p := ancestorOf(id); p != nil; p = ancestorOf(p) {
+		out = append(out, p.Clone())
+	}
+	return out
+}
+
+// Depth returns the number of ancestors of the resource id. The tenant has a depth of 0.
+func Depth(id ResourceId) int {
+	var n int
+	for p := ancestorOf(id); p != nil; p = ancestorOf(p) {
+		n++
+	}
+	return n
+}
+
+// IsAncestorOf tells whether the ancestor is a (proper) ancestor of the resource id, case-insensitively.
+func IsAncestorOf(ancestor, id ResourceId) bool {
+	for p := ancestorOf(id); p != nil; p = ancestorOf(p) {
+		if p.Equal(ancestor) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDescendantOf tells whether the resource id is a (proper) descendant of the ancestor, case-insensitively.
+func IsDescendantOf(id, ancestor ResourceId) bool {
+	return IsAncestorOf(ancestor, id)
+}
+
+// CommonAncestor returns the deepest resource id that contains all the resource ids, i.e. it is either equal to or an ancestor of
+// each of them. It returns nil if no resource id is given.
+func CommonAncestor(ids ...ResourceId) ResourceId {
+	if len(ids) == 0 {
+		return nil
+	}
+	for c := ids[0]; c != nil; c = ancestorOf(c) {
+		contains := true
+		for _, id := range ids[1:] {
+			if !c.Equal(id) && !IsAncestorOf(c, id) {
+				contains = false
+				break
+			}
+		}
+		if contains {
+			return c.Clone()
+		}
+	}
+	return nil
+}
+
+// Relative returns the path of the resource id below the base, without the leading "/".
+// E.g. "providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet1" for the subnet below the resource group.
+// The base must either equal to, or be an ancestor of the resource id. An empty string is returned if they are equal.
+func Relative(base, id ResourceId) (string, error) {
+	if !base.Equal(id) && !IsAncestorOf(base, id) {
+		return "", fmt.Errorf("%q is not under %q", id.String(), base.String())
+	}
+	segs := idSegments(id)
+	var rel []string
+	for _, seg := range segs[len(idSegments(base)):] {
+		rel = append(rel, seg.value)
+	}
+	return strings.Join(rel, "/"), nil
+}
+
+// Join parses the relative path onto the base, which is the reverse of Relative. The joined resource id must be a descendant of the base.
+func Join(base ResourceId, rel string) (ResourceId, error) {
+	if rel == "" {
+		return base.Clone(), nil
+	}
+	if strings.HasPrefix(rel, "/") {
+		return nil, fmt.Errorf(`relative path %q should not start with "/"`, rel)
+	}
+	for _, seg := range strings.Split(rel, "/") {
+		if seg == "." || seg == ".." {
+			return nil, fmt.Errorf("relative path %q should not contain %q segment", rel, seg)
+		}
+	}
+	var prefix string
+	if _, ok := base.(*TenantId); !ok {
+		prefix = base.String()
+	}
+	id, err := ParseResourceId(prefix + "/" + rel)
+	if err != nil {
+		return nil, fmt.Errorf("joining %q onto %q: %w", rel, base.String(), err)
+	}
+	if !IsDescendantOf(id, base) {
+		return nil, fmt.Errorf("joining %q onto %q: %q is not under the base", rel, base.String(), id.String())
+	}
+	return id, nil
+}
@@ -0,0 +1,283 @@
+package armid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAncestors(t *testing.T) {
+	cases := []struct {
+		name   string
+		input  string
+		expect []string
+	}{
+		{
+			name:  "Tenant",
+			input: "/",
+		},
+		{
+			name:   "Subscription",
+			input:  "/subscriptions/sub1",
+			expect: []string{"/"},
+		},
+		{
+			name:   "Management group",
+			input:  "/providers/Microsoft.Management/managementGroups/mg1",
+			expect: []string{"/"},
+		},
+		{
+			name:  "Root scope level resource",
+			input: "/subscriptions/sub1/resourceGroups/rg1/foos/foo1",
+			expect: []string{
+				"/subscriptions/sub1/resourceGroups/rg1",
+				"/subscriptions/sub1",
+				"/",
+			},
+		},
+		{
+			name:  "Nested child resource",
+			input: "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet1",
+			expect: []string{
+				"/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1",
+				"/subscriptions/sub1/resourceGroups/rg1",
+				"/subscriptions/sub1",
+				"/",
+			},
+		},
+		{
+			name:  "Extension resource",
+			input: "/subscriptions/sub1/providers/Microsoft.Network/virtualNetworks/vnet1/providers/Microsoft.Authorization/locks/lock1",
+			expect: []string{
+				"/subscriptions/sub1/providers/Microsoft.Network/virtualNetworks/vnet1",
+				"/subscriptions/sub1",
+				"/",
+			},
+		},
+		{
+			name:  "Provider",
+			input: "/providers/Microsoft.Management/managementGroups/mg1/providers/Microsoft.Foo",
+			expect: []string{
+				"/providers/Microsoft.Management/managementGroups/mg1",
+				"/",
+			},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := ParseResourceId(tt.input)
+			require.NoError(t, err)
+			var actual []string
+			for _, a := range Ancestors(id) {
+				actual = append(actual, a.String())
+			}
+			require.Equal(t, tt.expect, actual)
+			require.Equal(t, len(tt.expect), Depth(id))
+		})
+	}
+}
+
+func TestIsAncestorOf(t *testing.T) {
+	cases := []struct {
+		name     string
+		ancestor string
+		id       string
+		expect   bool
+	}{
+		{
+			name:     "Tenant contains everything",
+			ancestor: "/",
+			id:       "/subscriptions/sub1/resourceGroups/rg1",
+			expect:   true,
+		},
+		{
+			name:     "Resource group contains the nested resource case-insensitively",
+			ancestor: "/SUBSCRIPTIONS/SUB1/RESOURCEGROUPS/RG1",
+			id:       "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet1",
+			expect:   true,
+		},
+		{
+			name:     "Resource crosses the scope boundary",
+			ancestor: "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1",
+			id:       "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet1/providers/Microsoft.Authorization/locks/lock1",
+			expect:   true,
+		},
+		{
+			name:     "Not an ancestor of itself",
+			ancestor: "/subscriptions/sub1/resourceGroups/rg1",
+			id:       "/subscriptions/sub1/resourceGroups/rg1",
+		},
+		{
+			name:     "Different resource group",
+			ancestor: "/subscriptions/sub1/resourceGroups/rg2",
+			id:       "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Foo/foos/foo1",
+		},
+		{
+			name:     "Management group doesn't contain subscription",
+			ancestor: "/providers/Microsoft.Management/managementGroups/mg1",
+			id:       "/subscriptions/sub1",
+		},
+		{
+			name:     "Descendant is not an ancestor",
+			ancestor: "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Foo/foos/foo1",
+			id:       "/subscriptions/sub1/resourceGroups/rg1",
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ancestor, err := ParseResourceId(tt.ancestor)
+			require.NoError(t, err)
+			id, err := ParseResourceId(tt.id)
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, IsAncestorOf(ancestor, id))
+			require.Equal(t, tt.expect, IsDescendantOf(id, ancestor))
+		})
+	}
+}
+
+func TestCommonAncestor(t *testing.T) {
+	cases := []struct {
+		name   string
+		inputs []string
+		expect string
+	}{
+		{
+			name:   "Single",
+			inputs: []string{"/subscriptions/sub1/resourceGroups/rg1"},
+			expect: "/subscriptions/sub1/resourceGroups/rg1",
+		},
+		{
+			name: "Sibling subnets",
+			inputs: []string{
+				"/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet1",
+				"/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/VNET1/subnets/subnet2",
+			},
+			expect: "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1",
+		},
+		{
+			name: "One contains the other",
+			inputs: []string{
+				"/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Foo/foos/foo1",
+				"/subscriptions/sub1/resourceGroups/rg1",
+			},
+			expect: "/subscriptions/sub1/resourceGroups/rg1",
+		},
+		{
+			name: "Different resource groups",
+			inputs: []string{
+				"/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Foo/foos/foo1",
+				"/subscriptions/sub1/resourceGroups/rg2/providers/Microsoft.Foo/foos/foo1",
+				"/subscriptions/sub1/resourceGroups/rg1",
+			},
+			expect: "/subscriptions/sub1",
+		},
+		{
+			name: "Different root scopes",
+			inputs: []string{
+				"/subscriptions/sub1",
+				"/providers/Microsoft.Management/managementGroups/mg1",
+			},
+			expect: "/",
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids []ResourceId
+			for _, input := range tt.inputs {
+				id, err := ParseResourceId(input)
+				require.NoError(t, err)
+				ids = append(ids, id)
+			}
+			require.Equal(t, tt.expect, CommonAncestor(ids...).String())
+		})
+	}
+	require.Nil(t, CommonAncestor())
+}
+
+func TestRelativeAndJoin(t *testing.T) {
+	cases := []struct {
+		name   string
+		base   string
+		id     string
+		expect string
+		err    string
+	}{
+		{
+			name:   "Resource below resource group",
+			base:   "/subscriptions/sub1/resourceGroups/rg1",
+			id:     "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet1",
+			expect: "providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet1",
+		},
+		{
+			name:   "Child resource",
+			base:   "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1",
+			id:     "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet1",
+			expect: "subnets/subnet1",
+		},
+		{
+			name:   "Resource group below subscription",
+			base:   "/subscriptions/sub1",
+			id:     "/subscriptions/sub1/resourceGroups/rg1",
+			expect: "resourceGroups/rg1",
+		},
+		{
+			name:   "Below tenant",
+			base:   "/",
+			id:     "/subscriptions/sub1",
+			expect: "subscriptions/sub1",
+		},
+		{
+			name:   "Equal",
+			base:   "/subscriptions/sub1",
+			id:     "/SUBSCRIPTIONS/sub1",
+			expect: "",
+		},
+		{
+			name: "Not under",
+			base: "/subscriptions/sub1/resourceGroups/rg2",
+			id:   "/subscriptions/sub1/resourceGroups/rg1",
+			err:  `"/subscriptions/sub1/resourceGroups/rg1" is not under "/subscriptions/sub1/resourceGroups/rg2"`,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			base, err := ParseResourceId(tt.base)
+			require.NoError(t, err)
+			id, err := ParseResourceId(tt.id)
+			require.NoError(t, err)
+			rel, err := Relative(base, id)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, rel)
+			joined, err := Join(base, rel)
+			require.NoError(t, err)
+			require.True(t, joined.Equal(id))
+		})
+	}
+}
+
+func TestJoin_Error(t *testing.T) {
+	base, err := ParseResourceId("/subscriptions/sub1")
+	require.NoError(t, err)
+	_, err = Join(base, "/resourceGroups/rg1")
+	require.EqualError(t, err, `relative path "/resourceGroups/rg1" should not start with "/"`)
+	_, err = Join(base, "resourceGroups")
+	var perr *ParseError
+	require.ErrorAs(t, err, &perr)
+	require.Equal(t, ParseErrorMissingResourceName, perr.Kind)
+
+	rg, err := ParseResourceId("/subscriptions/s1/resourceGroups/rg")
+	require.NoError(t, err)
+	_, err = Join(rg, "../x")
+	require.EqualError(t, err, `relative path "../x" should not contain ".." segment`)
+	_, err = Join(rg, "providers/Microsoft.Foo/./foo1")
+	require.EqualError(t, err, `relative path "providers/Microsoft.Foo/./foo1" should not contain "." segment`)
+
+	p, err := ParseResourceId("/subscriptions/s1/providers/Microsoft.Foo")
+	require.NoError(t, err)
+	_, err = Join(p, "foos/foo1")
+	require.EqualError(t, err, `joining "foos/foo1" onto "/subscriptions/s1/providers/Microsoft.Foo": "/subscriptions/s1/providers/Microsoft.Foo/foos/foo1" is not under the base`)
+}
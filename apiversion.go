@@ -0,0 +1,124 @@
+package armid
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// APIVersion is an ARM API version, e.g. "2023-05-01" or "2023-05-01-preview".
+type APIVersion struct {
+	// Date is the date part of the API version.
+	Date time.Time
+
+	// Suffix is the optional suffix following the date without the leading "-", e.g. "preview", "beta".
+	Suffix string
+}
+
+// ParseAPIVersion parses the API version string literal, which is a date in the form of "YYYY-MM-DD", optionally followed by a suffix
+// (e.g. "-preview", "-beta", "-privatepreview", "-alpha").
+func ParseAPIVersion(input string) (APIVersion, error) {
+	if len(input) < 10 {
+		return APIVersion{}, fmt.Errorf("invalid API version %q: expect a date in the form of YYYY-MM-DD", input)
+	}
+	date, err := time.Parse("2006-01-02", input[:10])
+	if err != nil {
+		return APIVersion{}, fmt.Errorf("invalid API version %q: expect a date in the form of YYYY-MM-DD", input)
+	}
+	v := APIVersion{Date: date}
+	if rest := input[10:]; rest != "" {
+		if !strings.HasPrefix(rest, "-") || len(rest) == 1 {
+			return APIVersion{}, fmt.Errorf("invalid API version %q: expect a suffix led by \"-\" after the date", input)
+		}
+		v.Suffix = rest[1:]
+		for _, r := range v.Suffix {
+			if !isASCIILetterOrDigit(r) {
+				return APIVersion{}, fmt.Errorf("invalid API version %q: invalid character %q in suffix", input, r)
+			}
+		}
+	}
+	return v, nil
+}
+
+// String returns the API version string literal.
+func (v APIVersion) String() string {
+	s := v.Date.Format("2006-01-02")
+	if v.Suffix != "" {
+		s += "-" + v.Suffix
+	}
+	return s
+}
+
+// IsPreview tells whether the API version has a suffix, i.e. it is not a stable version.
+func (v APIVersion) IsPreview() bool {
+	return v.Suffix != ""
+}
+
+// suffixRanks ranks the well known suffixes, the higher the later. The unknown suffixes are ranked the same as "preview".
+var suffixRanks = map[string]int{
+	"alpha":          1,
+	"beta":           2,
+	"privatepreview": 3,
+	"preview":        4,
+	"":               5,
+}
+
+func suffixRank(suffix string) int {
+	if rank, ok := suffixRanks[strings.ToLower(suffix)]; ok {
+		return rank
+	}
+	return suffixRanks["preview"]
+}
+
+// Compare compares the API versions chronologically, returning -1, 0 or 1 if v is earlier than, the same as, or later than o.
+// Versions of the same date are ordered by the suffix: alpha < beta < privatepreview < preview < stable.
+func (v APIVersion) Compare(o APIVersion) int {
+	switch {
+	case v.Date.Before(o.Date):
+		return -1
+	case v.Date.After(o.Date):
+		return 1
+	}
+	vr, or := suffixRank(v.Suffix), suffixRank(o.Suffix)
+	switch {
+	case vr < or:
+		return -1
+	case vr > or:
+		return 1
+	}
+	return strings.Compare(strings.ToLower(v.Suffix), strings.ToLower(o.Suffix))
+}
+
+// TypeReference is a resource type with an API version, e.g. "Microsoft.Foo/foos/bars@2023-05-01-preview".
+type TypeReference struct {
+	Type       ResourceType
+	APIVersion APIVersion
+}
+
+// ParseTypeReference parses the versioned resource type reference, which is in the form of "<resource type>@<api version>".
+func ParseTypeReference(input string) (TypeReference, error) {
+	idx := strings.LastIndex(input, "@")
+	if idx == -1 {
+		return TypeReference{}, fmt.Errorf(`invalid type reference %q: missing "@" before the API version`, input)
+	}
+	rt, err := ParseResourceType(input[:idx])
+	if err != nil {
+		return TypeReference{}, fmt.Errorf("invalid type reference %q: %v", input, err)
+	}
+	v, err := ParseAPIVersion(input[idx+1:])
+	if err != nil {
+		return TypeReference{}, fmt.Errorf("invalid type reference %q: %v", input, err)
+	}
+	return TypeReference{Type: rt, APIVersion: v}, nil
+}
+
+// String returns the type reference string literal.
+func (r TypeReference) String() string {
+	return r.Type.String() + "@" + r.APIVersion.String()
+}
+
+// Matches tells whether the type of the reference matches the TypeString of the resource id case-insensitively.
+// The scope qualifier of the type, if any, is not taken into consideration.
+func (r TypeReference) Matches(id ResourceId) bool {
+	return strings.EqualFold(r.Type.Unscoped().String(), id.TypeString())
+}
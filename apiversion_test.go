@@ -0,0 +1,108 @@
+package armid
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAPIVersion(t *testing.T) {
+	cases := []struct {
+		name   string
+		input  string
+		expect APIVersion
+		err    string
+	}{
+		{
+			name:   "Stable",
+			input:  "2023-05-01",
+			expect: APIVersion{Date: time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:   "Preview",
+			input:  "2023-05-01-preview",
+			expect: APIVersion{Date: time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC), Suffix: "preview"},
+		},
+		{
+			name:  "Invalid date",
+			input: "2023-13-01",
+			err:   `invalid API version "2023-13-01": expect a date in the form of YYYY-MM-DD`,
+		},
+		{
+			name:  "Too short",
+			input: "2023-05",
+			err:   `invalid API version "2023-05": expect a date in the form of YYYY-MM-DD`,
+		},
+		{
+			name:  "Missing suffix separator",
+			input: "2023-05-01preview",
+			err:   `invalid API version "2023-05-01preview": expect a suffix led by "-" after the date`,
+		},
+		{
+			name:  "Invalid suffix",
+			input: "2023-05-01-pre.view",
+			err:   `invalid API version "2023-05-01-pre.view": invalid character '.' in suffix`,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := ParseAPIVersion(tt.input)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, v)
+			require.Equal(t, tt.input, v.String())
+		})
+	}
+}
+
+func TestAPIVersion_Compare(t *testing.T) {
+	ordered := []string{
+		"2022-01-01",
+		"2023-05-01-alpha",
+		"2023-05-01-beta",
+		"2023-05-01-privatepreview",
+		"2023-05-01-preview",
+		"2023-05-01",
+		"2023-06-01-preview",
+	}
+	for i := range ordered {
+		for j := range ordered {
+			vi, err := ParseAPIVersion(ordered[i])
+			require.NoError(t, err)
+			vj, err := ParseAPIVersion(ordered[j])
+			require.NoError(t, err)
+			expect := 0
+			if i < j {
+				expect = -1
+			} else if i > j {
+				expect = 1
+			}
+			require.Equal(t, expect, vi.Compare(vj), "%s vs %s", ordered[i], ordered[j])
+		}
+	}
+}
+
+func TestParseTypeReference(t *testing.T) {
+	ref, err := ParseTypeReference("Microsoft.Foo/foos/bars@2023-05-01-preview")
+	require.NoError(t, err)
+	require.Equal(t, "Microsoft.Foo/foos/bars", ref.Type.String())
+	require.Equal(t, "2023-05-01-preview", ref.APIVersion.String())
+	require.True(t, ref.APIVersion.IsPreview())
+	require.Equal(t, "Microsoft.Foo/foos/bars@2023-05-01-preview", ref.String())
+
+	id, err := ParseResourceId("/subscriptions/sub1/resourceGroups/rg1/providers/microsoft.foo/FOOS/foo1/bars/bar1")
+	require.NoError(t, err)
+	require.True(t, ref.Matches(id))
+	require.False(t, ref.Matches(id.Parent()))
+
+	_, err = ParseTypeReference("Microsoft.Foo/foos")
+	require.EqualError(t, err, `invalid type reference "Microsoft.Foo/foos": missing "@" before the API version`)
+	_, err = ParseTypeReference("Microsoft.Foo/foos@latest")
+	require.EqualError(t, err, `invalid type reference "Microsoft.Foo/foos@latest": invalid API version "latest": expect a date in the form of YYYY-MM-DD`)
+	_, err = ParseTypeReference("@2023-05-01")
+	require.EqualError(t, err, `invalid type reference "@2023-05-01": empty resource type`)
+}
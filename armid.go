@@ -68,18 +68,26 @@ type ResourceId interface {
 	Clone() ResourceId
 }
 
+// ParseResourceId parses the resource id string literal into a ResourceId.
+// The returned error is of type *ParseError.
 func ParseResourceId(id string) (ResourceId, error) {
 	if id == "/" {
 		return &TenantId{}, nil
 	}
 	if !strings.HasPrefix(id, "/") {
-		return nil, fmt.Errorf(`id should start with "/"`)
+		return nil, newParseError(id, nil, -1, ParseErrorMissingLeadingSlash, nil, `id should start with "/"`)
+	}
+	allSegs := strings.Split(id[1:], "/")
+	segs := allSegs
+
+	// index returns the index of the first segment of the remaining segments in allSegs
+	index := func() int {
+		return len(allSegs) - len(segs)
 	}
-	segs := strings.Split(id[1:], "/")
 
 	for idx, seg := range segs {
 		if seg == "" {
-			return nil, fmt.Errorf(`empty segment found behind %dth "/"`, idx+1)
+			return nil, newParseError(id, allSegs, idx, ParseErrorEmptySegment, nil, fmt.Sprintf(`empty segment found behind %dth "/"`, idx+1))
 		}
 	}
 
@@ -107,37 +115,52 @@ func ParseResourceId(id string) (ResourceId, error) {
 	}
 
 	var rid ResourceId = rootScope
-	var err error
 
 	// Root scope level resources, indicating the ARM 1st-class resource types
 	if !strings.EqualFold(segs[0], "providers") {
-		rid, segs, err = extendRootScopeByOneRP(rootScope, "", segs)
-		if err != nil {
-			return nil, fmt.Errorf("extending for root level RP: %v", err)
+		types, names, rest, ok := splitTypesAndNames(segs)
+		if !ok {
+			return nil, newParseError(id, allSegs, len(allSegs)-1, ParseErrorMissingResourceName, rootScope,
+				fmt.Sprintf("extending for root level RP: missing resource type name after type %s", types[len(types)-1]))
+		}
+		if _, ok := rootScope.(*TenantId); ok {
+			return nil, newParseError(id, allSegs, 0, ParseErrorUnsupportedRootScopeResource, rootScope,
+				fmt.Sprintf("extending for root level RP: unsupported type %T", rootScope))
 		}
+		segs = rest
+		rid = extendRootScope(rootScope, types, names)
 	}
 
 	for len(segs) != 0 {
 		if !strings.EqualFold(segs[0], "providers") {
-			return nil, fmt.Errorf(`scopes should be split by "/providers/"`)
+			return nil, newParseError(id, allSegs, index(), ParseErrorInvalidScopeSeparator, rid, `scopes should be split by "/providers/"`)
 		}
-		segs = segs[1:]
-		if len(segs) == 0 {
-			return nil, fmt.Errorf("missing provider namespace segment")
+		if len(segs) == 1 {
+			return nil, newParseError(id, allSegs, index(), ParseErrorMissingProviderNamespace, rid, "missing provider namespace segment")
 		}
-		rp := segs[0]
-		segs = segs[1:]
-		rid, segs, err = extendIdByOneRP(rid, rp, segs)
-		if err != nil {
-			return nil, fmt.Errorf("extending for RP %s: %v", rp, err)
+		rp := segs[1]
+		types, names, rest, ok := splitTypesAndNames(segs[2:])
+		if !ok {
+			return nil, newParseError(id, allSegs, len(allSegs)-1, ParseErrorMissingResourceName, rid,
+				fmt.Sprintf("extending for RP %s: missing resource type name after type %s", rp, types[len(types)-1]))
+		}
+		segs = rest
+		rid = &ScopedResourceId{
+			AttrParentScope: rid,
+			AttrProvider:    rp,
+			AttrTypes:       types,
+			AttrNames:       names,
 		}
 	}
 	return rid, nil
 }
 
-func extendRootScopeByOneRP(pid RootScope, rp string, segs []string) (ResourceId, []string, error) {
-	types := []string{}
-	names := []string{}
+// splitTypesAndNames consumes the type and name pairs from the segments, until reaching a "providers" segment or the end.
+// It returns the remaining segments, which are either empty or starting with "providers".
+// The returned ok is false if the last type has no name followed, in which case the last element of the returned types is that type.
+func splitTypesAndNames(segs []string) (types, names, rest []string, ok bool) {
+	types = []string{}
+	names = []string{}
 	for len(segs) != 0 {
 		if strings.EqualFold(segs[0], "providers") {
 			break
@@ -145,50 +168,28 @@ func extendRootScopeByOneRP(pid RootScope, rp string, segs []string) (ResourceId
 		types = append(types, segs[0])
 		segs = segs[1:]
 		if len(segs) == 0 {
-			return nil, nil, fmt.Errorf("missing resource type name after type %s", types[len(types)-1])
+			return types, names, nil, false
 		}
 		names = append(names, segs[0])
 		segs = segs[1:]
 	}
+	return types, names, segs, true
+}
+
+// extendRootScope sets the root scope level resource types and names to the root scope.
+func extendRootScope(pid RootScope, types, names []string) RootScope {
 	switch pid := pid.(type) {
 	case *ManagementGroup:
 		pid.AttrTypes = types
 		pid.AttrNames = names
-		return pid, segs, nil
 	case *SubscriptionId:
 		pid.AttrTypes = types
 		pid.AttrNames = names
-		return pid, segs, nil
 	case *ResourceGroup:
 		pid.AttrTypes = types
 		pid.AttrNames = names
-		return pid, segs, nil
-	default:
-		return nil, nil, fmt.Errorf("unsupported type %T", pid)
 	}
-}
-
-func extendIdByOneRP(pid ResourceId, rp string, segs []string) (ResourceId, []string, error) {
-	types := []string{}
-	names := []string{}
-	for len(segs) != 0 {
-		if strings.EqualFold(segs[0], "providers") {
-			break
-		}
-		types = append(types, segs[0])
-		segs = segs[1:]
-		if len(segs) == 0 {
-			return nil, nil, fmt.Errorf("missing resource type name after type %s", types[len(types)-1])
-		}
-		names = append(names, segs[0])
-		segs = segs[1:]
-	}
-	return &ScopedResourceId{
-		AttrParentScope: pid,
-		AttrProvider:    rp,
-		AttrTypes:       types,
-		AttrNames:       names,
-	}, segs, nil
+	return pid
 }
 
 // RootScope is a special resource id, that represents a root scope as defined by ARM.
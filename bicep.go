@@ -0,0 +1,192 @@
+package armid
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// BicepOptions controls how FormatBicepExisting renders the declarations.
+type BicepOptions struct {
+	// APIVersion returns the API version of the resource type, which is in the form of TypeString(), e.g. "Microsoft.Network/virtualNetworks/subnets".
+	// It is required.
+	APIVersion func(typeString string) string
+
+	// Flatten renders a child resource as a single declaration whose name is the slash-joined names, e.g. "vnet1/subnet1",
+	// instead of a chain of nested declarations linked by the "parent" property.
+	Flatten bool
+}
+
+// FormatBicepExisting renders the Bicep "existing" resource declarations for the resource id, e.g.
+//
+//	resource vnet1 'Microsoft.Network/virtualNetworks@2023-05-01' existing = {
+//	  name: 'vnet1'
+//	  scope: resourceGroup('sub1', 'rg1')
+//	}
+//
+// Other than the resource itself, its parent resources and the resources it is scoped to (for extension resources)
+// are declared as well. It returns the rendered declarations, together with the symbolic name of the resource, which is the last declaration.
+func FormatBicepExisting(id ResourceId, opts BicepOptions) (code string, symbol string, err error) {
+	if opts.APIVersion == nil {
+		return "", "", fmt.Errorf("APIVersion is required")
+	}
+	r := &bicepRenderer{
+		opts:    opts,
+		symbols: map[string]bool{},
+	}
+	symbol, err = r.declare(id)
+	if err != nil {
+		return "", "", err
+	}
+	return strings.Join(r.decls, "\n"), symbol, nil
+}
+
+type bicepRenderer struct {
+	opts    BicepOptions
+	decls   []string
+	symbols map[string]bool
+}
+
+// declare renders the declaration(s) of the resource id and returns its symbolic name.
+func (r *bicepRenderer) declare(id ResourceId) (string, error) {
+	switch id := id.(type) {
+	case *ResourceGroup:
+		if len(id.AttrTypes) != 0 {
+			return "", fmt.Errorf("root scope level resource %q can't be declared in Bicep", id.String())
+		}
+		return r.emit("Microsoft.Resources/resourceGroups", id.Name, id.Name, "scope", bicepFunction("subscription", id.SubscriptionId))
+	case *ManagementGroup:
+		if len(id.AttrTypes) != 0 {
+			return "", fmt.Errorf("root scope level resource %q can't be declared in Bicep", id.String())
+		}
+		return r.emit("Microsoft.Management/managementGroups", id.Name, id.Name, "scope", "tenant()")
+	case *ScopedResourceId:
+		if len(id.AttrTypes) == 0 {
+			return "", fmt.Errorf("provider level id %q can't be declared in Bicep", id.String())
+		}
+		scope, err := r.scopeExpression(id.AttrParentScope)
+		if err != nil {
+			return "", err
+		}
+		if r.opts.Flatten {
+			return r.emit(id.TypeString(), strings.Join(id.AttrNames, "/"), id.AttrNames[len(id.AttrNames)-1], "scope", scope)
+		}
+		var symbol string
+		for i := range id.AttrTypes {
+			typ := strings.Join(append([]string{id.AttrProvider}, id.AttrTypes[:i+1]...), "/")
+			if i == 0 {
+				symbol, err = r.emit(typ, id.AttrNames[i], id.AttrNames[i], "scope", scope)
+			} else {
+				symbol, err = r.emit(typ, id.AttrNames[i], id.AttrNames[i], "parent", symbol)
+			}
+			if err != nil {
+				return "", err
+			}
+		}
+		return symbol, nil
+	default:
+		return "", fmt.Errorf("%q can't be declared in Bicep", id.String())
+	}
+}
+
+// scopeExpression returns the Bicep expression used as the "scope" property of a resource, whose parent scope is the given id.
+func (r *bicepRenderer) scopeExpression(id ResourceId) (string, error) {
+	if !isBareRootScope(id) {
+		return r.declare(id)
+	}
+	switch id := id.(type) {
+	case *TenantId:
+		return "tenant()", nil
+	case *SubscriptionId:
+		return bicepFunction("subscription", id.Id), nil
+	case *ResourceGroup:
+		return bicepFunction("resourceGroup", id.SubscriptionId, id.Name), nil
+	case *ManagementGroup:
+		return bicepFunction("managementGroup", id.Name), nil
+	}
+	return "", fmt.Errorf("unsupported scope %q", id.String())
+}
+
+func (r *bicepRenderer) emit(typ, name, symbolHint, refKey, refValue string) (string, error) {
+	apiVersion := r.opts.APIVersion(typ)
+	if apiVersion == "" {
+		return "", fmt.Errorf("no API version for resource type %q", typ)
+	}
+	segs := strings.Split(typ, "/")
+	symbol := r.newSymbol(symbolHint, segs[len(segs)-1])
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "resource %s %s existing = {\n", symbol, bicepString(typ+"@"+apiVersion))
+	if refKey == "parent" {
+		fmt.Fprintf(&sb, "  parent: %s\n", refValue)
+	}
+	fmt.Fprintf(&sb, "  name: %s\n", bicepString(name))
+	if refKey == "scope" {
+		fmt.Fprintf(&sb, "  scope: %s\n", refValue)
+	}
+	sb.WriteString("}\n")
+	r.decls = append(r.decls, sb.String())
+	return symbol, nil
+}
+
+// bicepKeywords are the reserved identifiers that can't be used as symbolic names, including the namespaces and the scope functions
+// emitted by the renderer, which would otherwise be shadowed by the symbol.
+var bicepKeywords = map[string]bool{
+	"metadata": true, "targetScope": true, "resource": true, "module": true, "param": true, "var": true, "output": true,
+	"existing": true, "if": true, "for": true, "in": true, "true": true, "false": true, "null": true, "import": true,
+	"type": true, "func": true, "as": true, "with": true, "using": true,
+	"az": true, "sys": true, "tenant": true, "managementGroup": true, "subscription": true, "resourceGroup": true,
+}
+
+// newSymbol derives a unique symbolic name from the resource name, falling back to be based on the resource type.
+func (r *bicepRenderer) newSymbol(name, typ string) string {
+	base := bicepIdentifier(name)
+	if base == "" || unicode.IsDigit(rune(base[0])) || bicepKeywords[base] {
+		tbase := bicepIdentifier(typ)
+		if tbase == "" || unicode.IsDigit(rune(tbase[0])) {
+			tbase = "resource"
+		}
+		if base != "" {
+			base = tbase + strings.ToUpper(base[:1]) + base[1:]
+		} else {
+			base = tbase
+		}
+	}
+	symbol := base
+	for i := 2; r.symbols[symbol]; i++ {
+		symbol = fmt.Sprintf("%s%d", base, i)
+	}
+	r.symbols[symbol] = true
+	return symbol
+}
+
+// bicepIdentifier converts the input into a lower camel case identifier, e.g. "my-vnet_1" -> "myVnet1".
+func bicepIdentifier(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !(r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)))
+	})
+	var sb strings.Builder
+	for i, w := range words {
+		if i == 0 {
+			sb.WriteString(strings.ToLower(w[:1]) + w[1:])
+			continue
+		}
+		sb.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	return sb.String()
+}
+
+func bicepFunction(name string, args ...string) string {
+	l := make([]string, 0, len(args))
+	for _, arg := range args {
+		l = append(l, bicepString(arg))
+	}
+	return name + "(" + strings.Join(l, ", ") + ")"
+}
+
+func bicepString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `'`, `\'`)
+	s = strings.ReplaceAll(s, `${`, `\${`)
+	return "'" + s + "'"
+}
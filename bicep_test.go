@@ -0,0 +1,148 @@
+package armid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatBicepExisting(t *testing.T) {
+	apiVersion := func(string) string { return "2023-05-01" }
+	rg := &ResourceGroup{SubscriptionId: "sub1", Name: "rg1"}
+	subnet := &ScopedResourceId{
+		AttrParentScope: rg,
+		AttrProvider:    "Microsoft.Network",
+		AttrTypes:       []string{"virtualNetworks", "subnets"},
+		AttrNames:       []string{"my-vnet", "default"},
+	}
+	cases := []struct {
+		name   string
+		id     ResourceId
+		opts   BicepOptions
+		expect string
+		symbol string
+		err    string
+	}{
+		{
+			name:   "Resource group",
+			id:     rg,
+			opts:   BicepOptions{APIVersion: apiVersion},
+			symbol: "rg1",
+			expect: `resource rg1 'Microsoft.Resources/resourceGroups@2023-05-01' existing = {
+  name: 'rg1'
+  scope: subscription('sub1')
+}
+`,
+		},
+		{
+			name:   "Child resource",
+			id:     subnet,
+			opts:   BicepOptions{APIVersion: apiVersion},
+			symbol: "default",
+			expect: `resource myVnet 'Microsoft.Network/virtualNetworks@2023-05-01' existing = {
+  name: 'my-vnet'
+  scope: resourceGroup('sub1', 'rg1')
+}
+
+resource default 'Microsoft.Network/virtualNetworks/subnets@2023-05-01' existing = {
+  parent: myVnet
+  name: 'default'
+}
+`,
+		},
+		{
+			name:   "Flattened child resource",
+			id:     subnet,
+			opts:   BicepOptions{APIVersion: apiVersion, Flatten: true},
+			symbol: "default",
+			expect: `resource default 'Microsoft.Network/virtualNetworks/subnets@2023-05-01' existing = {
+  name: 'my-vnet/default'
+  scope: resourceGroup('sub1', 'rg1')
+}
+`,
+		},
+		{
+			name: "Extension resource",
+			id: &ScopedResourceId{
+				AttrParentScope: &ScopedResourceId{
+					AttrParentScope: &ManagementGroup{Name: "mg1"},
+					AttrProvider:    "Microsoft.Foo",
+					AttrTypes:       []string{"foos"},
+					AttrNames:       []string{"lock"},
+				},
+				AttrProvider: "Microsoft.Authorization",
+				AttrTypes:    []string{"locks"},
+				AttrNames:    []string{"lock"},
+			},
+			opts:   BicepOptions{APIVersion: apiVersion},
+			symbol: "lock2",
+			expect: `resource lock 'Microsoft.Foo/foos@2023-05-01' existing = {
+  name: 'lock'
+  scope: managementGroup('mg1')
+}
+
+resource lock2 'Microsoft.Authorization/locks@2023-05-01' existing = {
+  name: 'lock'
+  scope: lock
+}
+`,
+		},
+		{
+			name: "Resource under tenant",
+			id: &ScopedResourceId{
+				AttrParentScope: &TenantId{},
+				AttrProvider:    "Microsoft.Billing",
+				AttrTypes:       []string{"billingAccounts"},
+				AttrNames:       []string{"1234'5"},
+			},
+			opts:   BicepOptions{APIVersion: apiVersion},
+			symbol: "billingAccounts12345",
+			expect: `resource billingAccounts12345 'Microsoft.Billing/billingAccounts@2023-05-01' existing = {
+  name: '1234\'5'
+  scope: tenant()
+}
+`,
+		},
+		{
+			name: "Scope function name",
+			id: &ScopedResourceId{
+				AttrParentScope: rg,
+				AttrProvider:    "Microsoft.Network",
+				AttrTypes:       []string{"virtualNetworks"},
+				AttrNames:       []string{"resourceGroup"},
+			},
+			opts:   BicepOptions{APIVersion: apiVersion},
+			symbol: "virtualNetworksResourceGroup",
+			expect: `resource virtualNetworksResourceGroup 'Microsoft.Network/virtualNetworks@2023-05-01' existing = {
+  name: 'resourceGroup'
+  scope: resourceGroup('sub1', 'rg1')
+}
+`,
+		},
+		{
+			name: "Missing API version",
+			id:   rg,
+			opts: BicepOptions{APIVersion: func(string) string { return "" }},
+			err:  `no API version for resource type "Microsoft.Resources/resourceGroups"`,
+		},
+		{
+			name: "Subscription",
+			id:   &SubscriptionId{Id: "sub1"},
+			opts: BicepOptions{APIVersion: apiVersion},
+			err:  `"/subscriptions/sub1" can't be declared in Bicep`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			code, symbol, err := FormatBicepExisting(tt.id, tt.opts)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, code)
+			require.Equal(t, tt.symbol, symbol)
+		})
+	}
+}
@@ -0,0 +1,541 @@
+package armid
+
+import (
+	"bufio"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// binaryVersion is the version of the binary encoding, which leads the encoded bytes (or stream).
+const binaryVersion = 1
+
+// The tags of the root scopes in the binary encoding. They are persisted as part of the encoded bytes, so must never be changed.
+const (
+	binaryRootTenant          byte = 1
+	binaryRootManagementGroup byte = 2
+	binaryRootSubscription    byte = 3
+	binaryRootResourceGroup   byte = 4
+)
+
+// maxBinaryRecordSize is the max length of a record in the binary stream that the Decoder accepts, which guards against allocating an
+// arbitrary large buffer for a malformed (or malicious) length prefix. It is far beyond the length of any valid resource id.
+const maxBinaryRecordSize = 64 * 1024
+
+var (
+	_ encoding.BinaryMarshaler   = ID{}
+	_ encoding.BinaryUnmarshaler = &ID{}
+)
+
+// Dictionary is a shared dictionary of the well known strings (e.g. provider namespaces, resource types), which are encoded as an index
+// into the dictionary by the binary encoding, rather than the string itself. The lookup is case-sensitive.
+// The same dictionary must be used for both encoding and decoding.
+type Dictionary struct {
+	words []string
+	index map[string]int
+}
+
+// NewDictionary creates a Dictionary of the words. The order of the words matters.
+func NewDictionary(words ...string) *Dictionary {
+	d := &Dictionary{
+		words: append([]string{}, words...),
+		index: map[string]int{},
+	}
+	for i, w := range words {
+		if _, ok := d.index[w]; !ok {
+			d.index[w] = i
+		}
+	}
+	return d
+}
+
+// MarshalBinary encodes the ID without a dictionary. The zero value is encoded as an empty byte slice.
+func (i ID) MarshalBinary() ([]byte, error) {
+	if i.id == nil {
+		return []byte{}, nil
+	}
+	return EncodeBinary(i.id, nil), nil
+}
+
+// UnmarshalBinary decodes the ID that is encoded without a dictionary. An empty byte slice results into the zero value.
+func (i *ID) UnmarshalBinary(b []byte) error {
+	if len(b) == 0 {
+		*i = ID{}
+		return nil
+	}
+	id, err := DecodeBinary(b, nil)
+	if err != nil {
+		return err
+	}
+	*i = ID{id: id}
+	return nil
+}
+
+// EncodeBinary encodes the resource id into the compact binary form, which consists of the root scope tag, followed by the provider,
+// types and names of each scope, with varint lengths. The strings found in the optional dictionary are encoded as their indexes.
+// The casing overrides of the builtin literals (e.g. "SUBSCRIPTIONS") are preserved.
+func EncodeBinary(id ResourceId, dict *Dictionary) []byte {
+	b := []byte{binaryVersion}
+	levels := encodeLevels(id, dict)
+	return appendRecord(b, levels, 0)
+}
+
+// DecodeBinary decodes the resource id from the binary form encoded by EncodeBinary, using the same dictionary.
+func DecodeBinary(b []byte, dict *Dictionary) (ResourceId, error) {
+	if len(b) == 0 {
+		return nil, fmt.Errorf("invalid binary resource id: empty input")
+	}
+	if b[0] != binaryVersion {
+		return nil, fmt.Errorf("invalid binary resource id: unsupported version %d", b[0])
+	}
+	r := &binaryReader{b: b[1:], dict: dict}
+	levels, err := r.record(nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid binary resource id: %v", err)
+	}
+	if len(r.b) != 0 {
+		return nil, fmt.Errorf("invalid binary resource id: %d trailing bytes", len(r.b))
+	}
+	return levels.resourceId()
+}
+
+// Encoder writes a sequence of binary encoded resource ids to a stream. Each resource id only encodes the scopes that are different
+// from the previous one, i.e. consecutive resource ids sharing scopes are prefix compressed.
+type Encoder struct {
+	w       io.Writer
+	dict    *Dictionary
+	started bool
+	prev    [][]byte
+}
+
+// NewEncoder creates an Encoder that writes to w, with an optional dictionary.
+func NewEncoder(w io.Writer, dict *Dictionary) *Encoder {
+	return &Encoder{w: w, dict: dict}
+}
+
+// Encode writes the binary encoding of the resource id to the stream.
+func (e *Encoder) Encode(id ResourceId) error {
+	var b []byte
+	if !e.started {
+		b = append(b, binaryVersion)
+	}
+	levels := encodeLevels(id, e.dict)
+	var shared int
+	for shared < len(levels) && shared < len(e.prev) && string(levels[shared]) == string(e.prev[shared]) {
+		shared++
+	}
+	record := appendRecord(nil, levels, shared)
+	b = appendUvarint(b, uint64(len(record)))
+	b = append(b, record...)
+	if _, err := e.w.Write(b); err != nil {
+		return err
+	}
+	e.started = true
+	e.prev = levels
+	return nil
+}
+
+// Decoder reads a sequence of binary encoded resource ids from a stream written by an Encoder.
+type Decoder struct {
+	r       *bufio.Reader
+	dict    *Dictionary
+	started bool
+	prev    *binaryLevels
+}
+
+// NewDecoder creates a Decoder that reads from r, with the same dictionary as the Encoder.
+func NewDecoder(r io.Reader, dict *Dictionary) *Decoder {
+	return &Decoder{r: bufio.NewReader(r), dict: dict}
+}
+
+// Decode reads the next resource id from the stream. It returns io.EOF when there is no more resource id.
+func (d *Decoder) Decode() (ResourceId, error) {
+	if !d.started {
+		v, err := d.r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if v != binaryVersion {
+			return nil, fmt.Errorf("invalid binary resource id stream: unsupported version %d", v)
+		}
+		d.started = true
+	}
+	n, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return nil, err
+	}
+	if n > maxBinaryRecordSize {
+		return nil, fmt.Errorf("invalid binary resource id stream: record length %d exceeds the limit %d", n, maxBinaryRecordSize)
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	r := &binaryReader{b: b, dict: d.dict}
+	levels, err := r.record(d.prev)
+	if err != nil {
+		return nil, fmt.Errorf("invalid binary resource id stream: %v", err)
+	}
+	if len(r.b) != 0 {
+		return nil, fmt.Errorf("invalid binary resource id stream: %d trailing bytes in record", len(r.b))
+	}
+	id, err := levels.resourceId()
+	if err != nil {
+		return nil, err
+	}
+	d.prev = levels
+	return id, nil
+}
+
+// encodeLevels encodes the resource id as levels: the first level is the root scope itself, the second level is the root scope level resource
+// types and names (possibly empty), and each of the following levels is a scope led by "/providers/".
+//
+// A record is encoded as the number of levels shared with the previous record, the total number of levels, and the encoded levels that
+// are not shared.
+func encodeLevels(id ResourceId, dict *Dictionary) [][]byte {
+	var levels [][]byte
+	traverseScopes(id, func(id ResourceId) {
+		var root, attrs []byte
+		switch id := id.(type) {
+		case *TenantId:
+			root = []byte{binaryRootTenant}
+			attrs = appendPairs(nil, nil, nil, dict)
+		case *SubscriptionId:
+			root = []byte{binaryRootSubscription}
+			root = appendWord(root, id.subscriptionsLiteralOverride, dict)
+			root = appendString(root, id.Id)
+			attrs = appendPairs(nil, id.AttrTypes, id.AttrNames, dict)
+		case *ResourceGroup:
+			root = []byte{binaryRootResourceGroup}
+			root = appendWord(root, id.subscriptionsLiteralOverride, dict)
+			root = appendWord(root, id.resourceGroupsLiteralOverride, dict)
+			root = appendString(root, id.SubscriptionId)
+			root = appendString(root, id.Name)
+			attrs = appendPairs(nil, id.AttrTypes, id.AttrNames, dict)
+		case *ManagementGroup:
+			root = []byte{binaryRootManagementGroup}
+			root = appendWord(root, id.microsoftManagementLiteralOverride, dict)
+			root = appendWord(root, id.managementGroupsLiteralOverride, dict)
+			root = appendString(root, id.Name)
+			attrs = appendPairs(nil, id.AttrTypes, id.AttrNames, dict)
+		case *ScopedResourceId:
+			scope := appendWord(nil, id.AttrProvider, dict)
+			scope = appendPairs(scope, id.AttrTypes, id.AttrNames, dict)
+			levels = append(levels, scope)
+			return
+		}
+		levels = append(levels, root, attrs)
+	})
+	return levels
+}
+
+func appendRecord(b []byte, levels [][]byte, shared int) []byte {
+	b = appendUvarint(b, uint64(shared))
+	b = appendUvarint(b, uint64(len(levels)))
+	for _, level := range levels[shared:] {
+		b = append(b, level...)
+	}
+	return b
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+func appendString(b []byte, s string) []byte {
+	b = appendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// appendWord appends a string that might be in the dictionary. It is encoded as (index << 1 | 1) for a dictionary word, otherwise
+// (length << 1) followed by the string.
+func appendWord(b []byte, s string, dict *Dictionary) []byte {
+	if dict != nil && s != "" {
+		if idx, ok := dict.index[s]; ok {
+			return appendUvarint(b, uint64(idx)<<1|1)
+		}
+	}
+	b = appendUvarint(b, uint64(len(s))<<1)
+	return append(b, s...)
+}
+
+func appendPairs(b []byte, types, names []string, dict *Dictionary) []byte {
+	b = appendUvarint(b, uint64(len(types)))
+	for i := range types {
+		b = appendWord(b, types[i], dict)
+		b = appendString(b, names[i])
+	}
+	return b
+}
+
+// binaryLevels is the decoded levels of a resource id.
+type binaryLevels struct {
+	root   RootScope
+	attrs  ScopeComponents
+	scopes []ScopeComponents
+}
+
+func (l *binaryLevels) resourceId() (ResourceId, error) {
+	if err := validateTypesAndNames(l.attrs.Types, l.attrs.Names); err != nil {
+		return nil, fmt.Errorf("invalid binary resource id: %v", err)
+	}
+	var id ResourceId = l.root.Clone()
+	if len(l.attrs.Types) != 0 {
+		types, names := append([]string{}, l.attrs.Types...), append([]string{}, l.attrs.Names...)
+		switch root := id.(type) {
+		case *TenantId:
+			return nil, fmt.Errorf("invalid binary resource id: root scope level resource is not supported under the tenant")
+		case *SubscriptionId:
+			root.AttrTypes, root.AttrNames = types, names
+		case *ResourceGroup:
+			root.AttrTypes, root.AttrNames = types, names
+		case *ManagementGroup:
+			root.AttrTypes, root.AttrNames = types, names
+		}
+	}
+	for _, scope := range l.scopes {
+		var err error
+		if id, err = Extend(id, scope.Provider, scope.Types, scope.Names); err != nil {
+			return nil, fmt.Errorf("invalid binary resource id: %v", err)
+		}
+	}
+	return id, nil
+}
+
+type binaryReader struct {
+	b    []byte
+	dict *Dictionary
+}
+
+func (r *binaryReader) uvarint() (uint64, error) {
+	v, n := binary.Uvarint(r.b)
+	if n <= 0 {
+		return 0, fmt.Errorf("malformed varint")
+	}
+	r.b = r.b[n:]
+	return v, nil
+}
+
+func (r *binaryReader) bytes(n uint64) ([]byte, error) {
+	if n > uint64(len(r.b)) {
+		return nil, fmt.Errorf("unexpected end of input")
+	}
+	out := r.b[:n]
+	r.b = r.b[n:]
+	return out, nil
+}
+
+func (r *binaryReader) string() (string, error) {
+	n, err := r.uvarint()
+	if err != nil {
+		return "", err
+	}
+	b, err := r.bytes(n)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func (r *binaryReader) word() (string, error) {
+	v, err := r.uvarint()
+	if err != nil {
+		return "", err
+	}
+	if v&1 == 1 {
+		idx := v >> 1
+		if r.dict == nil || idx >= uint64(len(r.dict.words)) {
+			return "", fmt.Errorf("dictionary index %d out of range", idx)
+		}
+		return r.dict.words[idx], nil
+	}
+	b, err := r.bytes(v >> 1)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func (r *binaryReader) words(n int) ([]string, error) {
+	out := make([]string, n)
+	for i := range out {
+		var err error
+		if out[i], err = r.word(); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func (r *binaryReader) strings(n int) ([]string, error) {
+	out := make([]string, n)
+	for i := range out {
+		var err error
+		if out[i], err = r.string(); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func (r *binaryReader) pairs() ([]string, []string, error) {
+	n, err := r.uvarint()
+	if err != nil {
+		return nil, nil, err
+	}
+	if n > uint64(len(r.b)) {
+		return nil, nil, fmt.Errorf("unexpected end of input")
+	}
+	types, names := make([]string, n), make([]string, n)
+	for i := range types {
+		if types[i], err = r.word(); err != nil {
+			return nil, nil, err
+		}
+		if names[i], err = r.string(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return types, names, nil
+}
+
+func (r *binaryReader) root() (RootScope, error) {
+	tag, err := r.bytes(1)
+	if err != nil {
+		return nil, err
+	}
+	switch tag[0] {
+	case binaryRootTenant:
+		return &TenantId{}, nil
+	case binaryRootSubscription:
+		lits, err := r.words(1)
+		if err != nil {
+			return nil, err
+		}
+		vals, err := r.strings(1)
+		if err != nil {
+			return nil, err
+		}
+		if err := validateRootScope([]string{"subscriptions"}, lits, []string{"subscription id"}, vals); err != nil {
+			return nil, err
+		}
+		return &SubscriptionId{Id: vals[0], subscriptionsLiteralOverride: lits[0]}, nil
+	case binaryRootResourceGroup:
+		lits, err := r.words(2)
+		if err != nil {
+			return nil, err
+		}
+		vals, err := r.strings(2)
+		if err != nil {
+			return nil, err
+		}
+		if err := validateRootScope([]string{"subscriptions", "resourceGroups"}, lits, []string{"subscription id", "resource group name"}, vals); err != nil {
+			return nil, err
+		}
+		return &ResourceGroup{
+			SubscriptionId:                vals[0],
+			Name:                          vals[1],
+			subscriptionsLiteralOverride:  lits[0],
+			resourceGroupsLiteralOverride: lits[1],
+		}, nil
+	case binaryRootManagementGroup:
+		lits, err := r.words(2)
+		if err != nil {
+			return nil, err
+		}
+		vals, err := r.strings(1)
+		if err != nil {
+			return nil, err
+		}
+		if err := validateRootScope([]string{"Microsoft.Management", "managementGroups"}, lits, []string{"management group name"}, vals); err != nil {
+			return nil, err
+		}
+		return &ManagementGroup{
+			Name:                               vals[0],
+			microsoftManagementLiteralOverride: lits[0],
+			managementGroupsLiteralOverride:    lits[1],
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown root scope tag %d", tag[0])
+	}
+}
+
+// validateRootScope validates the decoded literal overrides against the builtin literals, and the decoded names of a root scope.
+func validateRootScope(builtins, lits, kinds, vals []string) error {
+	for i, lit := range lits {
+		if lit != "" && !strings.EqualFold(lit, builtins[i]) {
+			return fmt.Errorf("literal override %q doesn't match %q", lit, builtins[i])
+		}
+	}
+	for i, val := range vals {
+		if err := validateSegment(kinds[i], val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// record decodes a record, whose shared levels are taken from the previous levels.
+func (r *binaryReader) record(prev *binaryLevels) (*binaryLevels, error) {
+	shared, err := r.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	total, err := r.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	if total < 2 || shared > total {
+		return nil, fmt.Errorf("invalid level counts (shared: %d, total: %d)", shared, total)
+	}
+	var prevTotal uint64
+	if prev != nil {
+		prevTotal = uint64(2 + len(prev.scopes))
+	}
+	if shared > prevTotal {
+		return nil, fmt.Errorf("%d shared levels exceeds the %d levels of the previous record", shared, prevTotal)
+	}
+	if total-shared > uint64(len(r.b)) {
+		return nil, fmt.Errorf("unexpected end of input")
+	}
+
+	out := &binaryLevels{}
+	for i := uint64(0); i < total; i++ {
+		switch {
+		case i < shared:
+			switch i {
+			case 0:
+				out.root = prev.root
+			case 1:
+				out.attrs = prev.attrs
+			default:
+				out.scopes = append(out.scopes, prev.scopes[i-2])
+			}
+		case i == 0:
+			if out.root, err = r.root(); err != nil {
+				return nil, err
+			}
+		case i == 1:
+			if out.attrs.Types, out.attrs.Names, err = r.pairs(); err != nil {
+				return nil, err
+			}
+		default:
+			var scope ScopeComponents
+			if scope.Provider, err = r.word(); err != nil {
+				return nil, err
+			}
+			if scope.Types, scope.Names, err = r.pairs(); err != nil {
+				return nil, err
+			}
+			out.scopes = append(out.scopes, scope)
+		}
+	}
+	return out, nil
+}
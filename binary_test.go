@@ -0,0 +1,145 @@
+package armid
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var binaryTestIds = []string{
+	"/",
+	"/subscriptions/sub1",
+	"/subscriptions/sub1/tagNames/tag1",
+	"/subscriptions/sub1/resourceGroups/rg1",
+	"/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1",
+	"/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet1",
+	"/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet1/providers/Microsoft.Authorization/locks/lock1",
+	"/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet2",
+	"/subscriptions/sub1/providers/Microsoft.Foo",
+	"/providers/Microsoft.Management/managementGroups/mg1",
+	"/providers/Microsoft.Management/managementGroups/mg1/providers/Microsoft.Foo/foos/foo1",
+	"/providers/Microsoft.Foo/foos/foo1",
+}
+
+func TestEncodeBinary(t *testing.T) {
+	dict := NewDictionary("Microsoft.Network", "virtualNetworks", "subnets", "Microsoft.Authorization", "locks")
+	for _, input := range binaryTestIds {
+		t.Run(input, func(t *testing.T) {
+			id, err := ParseResourceId(input)
+			require.NoError(t, err)
+
+			b := EncodeBinary(id, nil)
+			out, err := DecodeBinary(b, nil)
+			require.NoError(t, err)
+			require.Equal(t, id, out)
+
+			db := EncodeBinary(id, dict)
+			require.LessOrEqual(t, len(db), len(b))
+			out, err = DecodeBinary(db, dict)
+			require.NoError(t, err)
+			require.Equal(t, id, out)
+		})
+	}
+}
+
+func TestEncodeBinary_Overrides(t *testing.T) {
+	id, err := ParseResourceId("/subscriptions/sub1/resourceGroups/rg1/foos/foo1/providers/Microsoft.Foo/bars/bar1")
+	require.NoError(t, err)
+	require.NoError(t, id.Normalize("/SUBSCRIPTIONS/resourcegroups/FOOS/microsoft.foo/BARS"))
+	out, err := DecodeBinary(EncodeBinary(id, nil), nil)
+	require.NoError(t, err)
+	require.Equal(t, "/SUBSCRIPTIONS/sub1/resourcegroups/rg1/FOOS/foo1/providers/microsoft.foo/BARS/bar1", out.String())
+
+	mg, err := ParseResourceId("/providers/Microsoft.Management/managementGroups/mg1")
+	require.NoError(t, err)
+	require.NoError(t, mg.Normalize("/MICROSOFT.MANAGEMENT/MANAGEMENTGROUPS"))
+	out, err = DecodeBinary(EncodeBinary(mg, nil), nil)
+	require.NoError(t, err)
+	require.Equal(t, mg.String(), out.String())
+}
+
+func TestDecodeBinary_Error(t *testing.T) {
+	id, err := ParseResourceId("/subscriptions/sub1/providers/Microsoft.Foo/foos/foo1")
+	require.NoError(t, err)
+	b := EncodeBinary(id, NewDictionary("Microsoft.Foo"))
+
+	_, err = DecodeBinary(nil, nil)
+	require.EqualError(t, err, "invalid binary resource id: empty input")
+	_, err = DecodeBinary([]byte{2}, nil)
+	require.EqualError(t, err, "invalid binary resource id: unsupported version 2")
+	_, err = DecodeBinary(b[:len(b)-1], nil)
+	require.EqualError(t, err, "invalid binary resource id: dictionary index 0 out of range")
+	_, err = DecodeBinary(b[:len(b)-2], NewDictionary("Microsoft.Foo"))
+	require.EqualError(t, err, "invalid binary resource id: unexpected end of input")
+	_, err = DecodeBinary(append(b, 0), NewDictionary("Microsoft.Foo"))
+	require.EqualError(t, err, "invalid binary resource id: 1 trailing bytes")
+	_, err = DecodeBinary([]byte{binaryVersion, 0, 2, 9, 0}, nil)
+	require.EqualError(t, err, "invalid binary resource id: unknown root scope tag 9")
+	_, err = DecodeBinary([]byte{1, 0, 2, 3, 6, 'a', '/', 'b', 2, 's', '1', 0}, nil)
+	require.EqualError(t, err, `invalid binary resource id: literal override "a/b" doesn't match "subscriptions"`)
+	_, err = DecodeBinary([]byte{1, 0, 2, 3, 0, 0, 0}, nil)
+	require.EqualError(t, err, "invalid binary resource id: empty subscription id")
+}
+
+func TestID_Binary(t *testing.T) {
+	id, err := ParseID("/subscriptions/sub1/resourceGroups/rg1")
+	require.NoError(t, err)
+	b, err := id.MarshalBinary()
+	require.NoError(t, err)
+	var out ID
+	require.NoError(t, out.UnmarshalBinary(b))
+	require.True(t, id.Equal(out))
+
+	b, err = ID{}.MarshalBinary()
+	require.NoError(t, err)
+	require.Empty(t, b)
+	require.NoError(t, out.UnmarshalBinary(b))
+	require.True(t, out.IsZero())
+}
+
+func TestEncoder(t *testing.T) {
+	dict := NewDictionary("Microsoft.Network", "virtualNetworks", "subnets")
+	var ids []ResourceId
+	var independent int
+	for _, input := range binaryTestIds {
+		id, err := ParseResourceId(input)
+		require.NoError(t, err)
+		ids = append(ids, id)
+		independent += len(EncodeBinary(id, dict))
+	}
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf, dict)
+	for _, id := range ids {
+		require.NoError(t, enc.Encode(id))
+	}
+	require.Less(t, buf.Len(), independent)
+
+	dec := NewDecoder(&buf, dict)
+	for _, id := range ids {
+		out, err := dec.Decode()
+		require.NoError(t, err)
+		require.Equal(t, id, out)
+	}
+	_, err := dec.Decode()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestDecoder_Error(t *testing.T) {
+	id, err := ParseResourceId("/subscriptions/sub1/resourceGroups/rg1")
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, NewEncoder(&buf, nil).Encode(id))
+	_, err = NewDecoder(bytes.NewReader(buf.Bytes()[:buf.Len()-1]), nil).Decode()
+	require.Equal(t, io.ErrUnexpectedEOF, err)
+
+	// A record that shares levels without a previous record.
+	_, err = NewDecoder(bytes.NewReader([]byte{binaryVersion, 2, 1, 2}), nil).Decode()
+	require.EqualError(t, err, "invalid binary resource id stream: 1 shared levels exceeds the 0 levels of the previous record")
+
+	// A record with an oversized length prefix.
+	_, err = NewDecoder(bytes.NewReader([]byte{binaryVersion, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f}), nil).Decode()
+	require.EqualError(t, err, "invalid binary resource id stream: record length 9223372036854775807 exceeds the limit 65536")
+}
@@ -0,0 +1,176 @@
+package armid
+
+import (
+	"fmt"
+	"strings"
+)
+
+// validateSegment validates a single segment of the resource id.
+func validateSegment(kind, seg string) error {
+	if seg == "" {
+		return fmt.Errorf("empty %s", kind)
+	}
+	if strings.Contains(seg, "/") {
+		return fmt.Errorf(`%s %q contains "/"`, kind, seg)
+	}
+	return nil
+}
+
+// validateTypesAndNames validates the resource types and names that are used together to build a resource id.
+func validateTypesAndNames(types, names []string) error {
+	if len(types) != len(names) {
+		return fmt.Errorf("the number of types (%d) and names (%d) mismatch", len(types), len(names))
+	}
+	for i := range types {
+		if err := validateSegment("resource type", types[i]); err != nil {
+			return err
+		}
+		if strings.EqualFold(types[i], "providers") {
+			return fmt.Errorf(`resource type can't be %q`, types[i])
+		}
+		if err := validateSegment("resource name", names[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewSubscriptionId creates a SubscriptionId.
+func NewSubscriptionId(subscriptionId string) (*SubscriptionId, error) {
+	if err := validateSegment("subscription id", subscriptionId); err != nil {
+		return nil, err
+	}
+	return &SubscriptionId{Id: subscriptionId}, nil
+}
+
+// NewResourceGroup creates a ResourceGroup.
+func NewResourceGroup(subscriptionId, name string) (*ResourceGroup, error) {
+	if err := validateSegment("subscription id", subscriptionId); err != nil {
+		return nil, err
+	}
+	if err := validateSegment("resource group name", name); err != nil {
+		return nil, err
+	}
+	return &ResourceGroup{SubscriptionId: subscriptionId, Name: name}, nil
+}
+
+// NewManagementGroup creates a ManagementGroup.
+func NewManagementGroup(name string) (*ManagementGroup, error) {
+	if err := validateSegment("management group name", name); err != nil {
+		return nil, err
+	}
+	return &ManagementGroup{Name: name}, nil
+}
+
+// NewScopedResourceId creates a ScopedResourceId under the parent scope. The types and names must be of the same length.
+// The slices are copied, so that the returned id doesn't alias them.
+func NewScopedResourceId(parentScope ResourceId, provider string, types, names []string) (*ScopedResourceId, error) {
+	if parentScope == nil {
+		return nil, fmt.Errorf("nil parent scope")
+	}
+	if err := validateSegment("provider namespace", provider); err != nil {
+		return nil, err
+	}
+	if err := validateTypesAndNames(types, names); err != nil {
+		return nil, err
+	}
+	return &ScopedResourceId{
+		AttrParentScope: parentScope,
+		AttrProvider:    provider,
+		AttrTypes:       append([]string{}, types...),
+		AttrNames:       append([]string{}, names...),
+	}, nil
+}
+
+// Builder builds a resource id step by step, e.g.
+//
+//	Subscription("0000").ResourceGroup("rg1").Provider("Microsoft.Network").Child("virtualNetworks", "vnet1").Child("subnets", "subnet1").Build()
+//
+// Each step returns a new Builder, so that a Builder can be reused as a common prefix.
+// The first error encountered is recorded and returned by Build.
+type Builder struct {
+	id  ResourceId
+	err error
+}
+
+// Tenant starts building from the tenant scope.
+func Tenant() *Builder {
+	return &Builder{id: &TenantId{}}
+}
+
+// Subscription starts building from the subscription scope.
+func Subscription(subscriptionId string) *Builder {
+	id, err := NewSubscriptionId(subscriptionId)
+	return &Builder{id: id, err: err}
+}
+
+// From starts building from an existing resource id, which is cloned.
+func From(id ResourceId) *Builder {
+	if id == nil {
+		return &Builder{err: fmt.Errorf("nil resource id")}
+	}
+	return &Builder{id: id.Clone()}
+}
+
+func (b *Builder) next(f func(id ResourceId) (ResourceId, error)) *Builder {
+	if b.err != nil {
+		return b
+	}
+	id, err := f(b.id)
+	if err != nil {
+		return &Builder{err: err}
+	}
+	return &Builder{id: id}
+}
+
+// ResourceGroup moves to the resource group scope. It is only allowed right after Subscription.
+func (b *Builder) ResourceGroup(name string) *Builder {
+	return b.next(func(id ResourceId) (ResourceId, error) {
+		sub, ok := id.(*SubscriptionId)
+		if !ok || len(sub.AttrTypes) != 0 {
+			return nil, fmt.Errorf("resource group can only be built under a subscription, got %q", id.String())
+		}
+		return NewResourceGroup(sub.Id, name)
+	})
+}
+
+// ManagementGroup moves to the management group scope. It is only allowed right after Tenant.
+func (b *Builder) ManagementGroup(name string) *Builder {
+	return b.next(func(id ResourceId) (ResourceId, error) {
+		if _, ok := id.(*TenantId); !ok {
+			return nil, fmt.Errorf("management group can only be built under the tenant, got %q", id.String())
+		}
+		return NewManagementGroup(name)
+	})
+}
+
+// Provider starts a new scope of the provider namespace, under the current resource id.
+func (b *Builder) Provider(namespace string) *Builder {
+	return b.next(func(id ResourceId) (ResourceId, error) {
+		return Extend(id, namespace, nil, nil)
+	})
+}
+
+// Child appends a resource type and name to the current resource id. Before any call to Provider, this builds a root scope level resource.
+func (b *Builder) Child(typ, name string) *Builder {
+	return b.next(func(id ResourceId) (ResourceId, error) {
+		return Child(id, typ, name)
+	})
+}
+
+// Build returns the built resource id, or the first error encountered.
+func (b *Builder) Build() (ResourceId, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return b.id.Clone(), nil
+}
+
+// MustBuild is like Build but panics on error.
+func (b *Builder) MustBuild() ResourceId {
+	id, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
@@ -0,0 +1,140 @@
+package armid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder(t *testing.T) {
+	cases := []struct {
+		name    string
+		builder *Builder
+		expect  string
+		err     string
+	}{
+		{
+			name:    "Tenant",
+			builder: Tenant(),
+			expect:  "/",
+		},
+		{
+			name:    "Management group",
+			builder: Tenant().ManagementGroup("mg1"),
+			expect:  "/providers/Microsoft.Management/managementGroups/mg1",
+		},
+		{
+			name:    "Resource group",
+			builder: Subscription("sub1").ResourceGroup("rg1"),
+			expect:  "/subscriptions/sub1/resourceGroups/rg1",
+		},
+		{
+			name:    "Nested child resource",
+			builder: Subscription("sub1").ResourceGroup("rg1").Provider("Microsoft.Network").Child("virtualNetworks", "vnet1").Child("subnets", "subnet1"),
+			expect:  "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet1",
+		},
+		{
+			name:    "Root scope level resource",
+			builder: Subscription("sub1").Child("tagNames", "tag1"),
+			expect:  "/subscriptions/sub1/tagNames/tag1",
+		},
+		{
+			name:    "Extension resource",
+			builder: Subscription("sub1").Provider("Microsoft.Foo").Child("foos", "foo1").Provider("Microsoft.Bar").Child("bars", "bar1"),
+			expect:  "/subscriptions/sub1/providers/Microsoft.Foo/foos/foo1/providers/Microsoft.Bar/bars/bar1",
+		},
+		{
+			name:    "Provider",
+			builder: Tenant().Provider("Microsoft.Foo"),
+			expect:  "/providers/Microsoft.Foo",
+		},
+		{
+			name:    "Empty subscription",
+			builder: Subscription("").ResourceGroup("rg1"),
+			err:     "empty subscription id",
+		},
+		{
+			name:    "Name contains slash",
+			builder: Subscription("sub1").Provider("Microsoft.Foo").Child("foos", "a/b"),
+			err:     `resource name "a/b" contains "/"`,
+		},
+		{
+			name:    "Type is providers",
+			builder: Subscription("sub1").Provider("Microsoft.Foo").Child("providers", "foo1"),
+			err:     `resource type can't be "providers"`,
+		},
+		{
+			name:    "Resource group under resource group",
+			builder: Subscription("sub1").ResourceGroup("rg1").ResourceGroup("rg2"),
+			err:     `resource group can only be built under a subscription, got "/subscriptions/sub1/resourceGroups/rg1"`,
+		},
+		{
+			name:    "Management group under subscription",
+			builder: Subscription("sub1").ManagementGroup("mg1"),
+			err:     `management group can only be built under the tenant, got "/subscriptions/sub1"`,
+		},
+		{
+			name:    "Provider without type",
+			builder: Subscription("sub1").Provider("Microsoft.Foo").Provider("Microsoft.Bar"),
+			err:     `provider "Microsoft.Foo" has no resource type`,
+		},
+		{
+			name:    "Child under tenant",
+			builder: Tenant().Child("foos", "foo1"),
+			err:     "root scope level resource is not supported under the tenant",
+		},
+		{
+			name:    "First error wins",
+			builder: Subscription("sub1").Provider("").Child("", ""),
+			err:     "empty provider namespace",
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := tt.builder.Build()
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, id.String())
+			if tt.expect != "/" {
+				pid, err := ParseResourceId(tt.expect)
+				require.NoError(t, err)
+				require.Equal(t, pid, id)
+			}
+		})
+	}
+}
+
+func TestBuilder_Reuse(t *testing.T) {
+	vnet := Subscription("sub1").ResourceGroup("rg1").Provider("Microsoft.Network").Child("virtualNetworks", "vnet1")
+	subnet1 := vnet.Child("subnets", "subnet1").MustBuild()
+	subnet2 := vnet.Child("subnets", "subnet2").MustBuild()
+	require.Equal(t, "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet1", subnet1.String())
+	require.Equal(t, "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet2", subnet2.String())
+	require.Equal(t, "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1", vnet.MustBuild().String())
+
+	id, err := From(subnet1).Provider("Microsoft.Authorization").Child("locks", "lock1").Build()
+	require.NoError(t, err)
+	require.Equal(t, subnet1.String()+"/providers/Microsoft.Authorization/locks/lock1", id.String())
+}
+
+func TestNewScopedResourceId(t *testing.T) {
+	rg, err := NewResourceGroup("sub1", "rg1")
+	require.NoError(t, err)
+	types, names := []string{"foos"}, []string{"foo1"}
+	id, err := NewScopedResourceId(rg, "Microsoft.Foo", types, names)
+	require.NoError(t, err)
+	names[0] = "changed"
+	require.Equal(t, "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Foo/foos/foo1", id.String())
+
+	_, err = NewScopedResourceId(rg, "Microsoft.Foo", []string{"foos", "bars"}, []string{"foo1"})
+	require.EqualError(t, err, "the number of types (2) and names (1) mismatch")
+	_, err = NewScopedResourceId(nil, "Microsoft.Foo", nil, nil)
+	require.EqualError(t, err, "nil parent scope")
+	_, err = NewResourceGroup("sub1", "")
+	require.EqualError(t, err, "empty resource group name")
+	_, err = NewManagementGroup("a/b")
+	require.EqualError(t, err, `management group name "a/b" contains "/"`)
+}
@@ -0,0 +1,124 @@
+package armid
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CollectionId represents a collection (i.e. list) endpoint of a resource type, which is a resource id followed by a trailing
+// resource type without a name, e.g.
+//
+//   - /subscriptions/0000/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks
+//   - /subscriptions/0000/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets
+//   - /subscriptions/0000/resourceGroups
+type CollectionId struct {
+	// Parent is the resource id that the collection is listed under.
+	// For a collection that starts a new provider scope (e.g. ".../providers/Microsoft.Network/virtualNetworks"), this is the parent scope.
+	Parent ResourceId
+
+	// Provider is the provider namespace of a collection that starts a new provider scope. It is empty if the collection is within the
+	// provider (or root scope) of the Parent.
+	Provider string
+
+	// Type is the resource type of the collection items, e.g. "virtualNetworks".
+	Type string
+}
+
+// ParseCollectionId parses the collection id string literal.
+func ParseCollectionId(input string) (*CollectionId, error) {
+	parent, typ, err := splitTrailingSegment(input, "is a resource id rather than a collection")
+	if err != nil {
+		return nil, err
+	}
+	if sid, ok := parent.(*ScopedResourceId); ok && len(sid.AttrTypes) == 0 {
+		return &CollectionId{Parent: sid.AttrParentScope, Provider: sid.AttrProvider, Type: typ}, nil
+	}
+	return &CollectionId{Parent: parent, Type: typ}, nil
+}
+
+// splitTrailingSegment parses the input that is a resource id followed by a single trailing segment, e.g. a collection type or an action.
+// The resource id and the trailing segment are returned. If the input is a resource id on its own, the error reports it with the reason.
+func splitTrailingSegment(input, reason string) (ResourceId, string, error) {
+	if _, err := ParseResourceId(input); err == nil {
+		return nil, "", fmt.Errorf("%q %s", input, reason)
+	} else {
+		var perr *ParseError
+		if !errors.As(err, &perr) || perr.Kind != ParseErrorMissingResourceName || perr.SegmentIndex != len(strings.Split(input, "/"))-2 {
+			return nil, "", err
+		}
+	}
+
+	idx := strings.LastIndex(input, "/")
+	var id ResourceId = &TenantId{}
+	if prefix := input[:idx]; prefix != "" {
+		var err error
+		if id, err = ParseResourceId(prefix); err != nil {
+			return nil, "", err
+		}
+	}
+	return id, input[idx+1:], nil
+}
+
+// String returns the collection id literal.
+func (c *CollectionId) String() string {
+	var prefix string
+	if _, ok := c.Parent.(*TenantId); !ok {
+		prefix = c.Parent.String()
+	}
+	if c.Provider != "" {
+		return prefix + "/providers/" + c.Provider + "/" + c.Type
+	}
+	return prefix + "/" + c.Type
+}
+
+// ItemType returns the resource type of the collection items.
+func (c *CollectionId) ItemType() ResourceType {
+	if c.Provider != "" {
+		rt := ResourceType{
+			Namespace: c.Provider,
+			Types:     []string{c.Type},
+		}
+		if !isBareRootScope(c.Parent) {
+			scope := ResourceTypeOf(c.Parent)
+			rt.Scope = &scope
+		}
+		return rt
+	}
+	if _, ok := c.Parent.(*TenantId); ok {
+		return ResourceType{
+			Namespace: c.Parent.Provider(),
+			Types:     []string{c.Type},
+		}
+	}
+	return ResourceTypeOf(c.Parent).Child(c.Type)
+}
+
+// Item returns the resource id of the item of the given name in this collection.
+func (c *CollectionId) Item(name string) (ResourceId, error) {
+	if err := validateSegment("resource name", name); err != nil {
+		return nil, err
+	}
+	return ParseResourceId(c.String() + "/" + name)
+}
+
+// ProviderRegistrationId returns the provider level resource id under the subscription, where the resource provider of the resource id
+// is registered, e.g. "/subscriptions/0000/providers/Microsoft.Network" for
+// "/subscriptions/0000/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1".
+func ProviderRegistrationId(id ResourceId) (*ScopedResourceId, error) {
+	var subId string
+	switch root := id.RootScope().(type) {
+	case *SubscriptionId:
+		subId = root.Id
+	case *ResourceGroup:
+		subId = root.SubscriptionId
+	default:
+		return nil, fmt.Errorf("%q is not under a subscription", id.String())
+	}
+	return &ScopedResourceId{
+		AttrParentScope: &SubscriptionId{Id: subId},
+		AttrProvider:    id.Provider(),
+		AttrTypes:       []string{},
+		AttrNames:       []string{},
+	}, nil
+}
@@ -0,0 +1,118 @@
+package armid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCollectionId(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		parent   string
+		provider string
+		typ      string
+		itemType string
+		item     string
+		err      string
+	}{
+		{
+			name:     "Top level resources",
+			input:    "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks",
+			parent:   "/subscriptions/sub1/resourceGroups/rg1",
+			provider: "Microsoft.Network",
+			typ:      "virtualNetworks",
+			itemType: "Microsoft.Network/virtualNetworks",
+			item:     "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/x",
+		},
+		{
+			name:     "Child resources",
+			input:    "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets",
+			parent:   "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1",
+			typ:      "subnets",
+			itemType: "Microsoft.Network/virtualNetworks/subnets",
+			item:     "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/x",
+		},
+		{
+			name:     "Extension resources",
+			input:    "/subscriptions/sub1/providers/Microsoft.Foo/foos/foo1/providers/Microsoft.Authorization/locks",
+			parent:   "/subscriptions/sub1/providers/Microsoft.Foo/foos/foo1",
+			provider: "Microsoft.Authorization",
+			typ:      "locks",
+			itemType: "Microsoft.Foo/foos/providers/Microsoft.Authorization/locks",
+			item:     "/subscriptions/sub1/providers/Microsoft.Foo/foos/foo1/providers/Microsoft.Authorization/locks/x",
+		},
+		{
+			name:     "Resource groups",
+			input:    "/subscriptions/sub1/resourceGroups",
+			parent:   "/subscriptions/sub1",
+			typ:      "resourceGroups",
+			itemType: "Microsoft.Resources/subscriptions/resourceGroups",
+			item:     "/subscriptions/sub1/resourceGroups/x",
+		},
+		{
+			name:     "Subscriptions",
+			input:    "/subscriptions",
+			parent:   "/",
+			typ:      "subscriptions",
+			itemType: "Microsoft.Resources/subscriptions",
+			item:     "/subscriptions/x",
+		},
+		{
+			name:     "Management groups",
+			input:    "/providers/Microsoft.Management/managementGroups",
+			parent:   "/",
+			provider: "Microsoft.Management",
+			typ:      "managementGroups",
+			itemType: "Microsoft.Management/managementGroups",
+			item:     "/providers/Microsoft.Management/managementGroups/x",
+		},
+		{
+			name:  "Resource id",
+			input: "/subscriptions/sub1/resourceGroups/rg1",
+			err:   `"/subscriptions/sub1/resourceGroups/rg1" is a resource id rather than a collection`,
+		},
+		{
+			name:  "Invalid",
+			input: "/subscriptions/sub1//foos",
+			err:   `empty segment found behind 3th "/"`,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseCollectionId(tt.input)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.parent, c.Parent.String())
+			require.Equal(t, tt.provider, c.Provider)
+			require.Equal(t, tt.typ, c.Type)
+			require.Equal(t, tt.input, c.String())
+			require.Equal(t, tt.itemType, c.ItemType().String())
+			item, err := c.Item("x")
+			require.NoError(t, err)
+			require.Equal(t, tt.item, item.String())
+			require.True(t, c.ItemType().Equal(ResourceTypeOf(item)))
+		})
+	}
+}
+
+func TestProviderRegistrationId(t *testing.T) {
+	id, err := ParseResourceId("/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1")
+	require.NoError(t, err)
+	pid, err := ProviderRegistrationId(id)
+	require.NoError(t, err)
+	require.Equal(t, "/subscriptions/sub1/providers/Microsoft.Network", pid.String())
+	require.Equal(t, KindProvider, KindOf(pid))
+	expect, err := ParseResourceId("/subscriptions/sub1/providers/Microsoft.Network")
+	require.NoError(t, err)
+	require.Equal(t, expect, pid)
+
+	id, err = ParseResourceId("/providers/Microsoft.Management/managementGroups/mg1/providers/Microsoft.Foo/foos/foo1")
+	require.NoError(t, err)
+	_, err = ProviderRegistrationId(id)
+	require.EqualError(t, err, `"/providers/Microsoft.Management/managementGroups/mg1/providers/Microsoft.Foo/foos/foo1" is not under a subscription`)
+}
@@ -0,0 +1,160 @@
+package armid
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ComponentsJSONSchema is the JSON Schema of the Components.
+//
+//go:embed components.schema.json
+var ComponentsJSONSchema string
+
+// Components is the structured representation of a resource id, e.g.
+//
+//	{
+//	  "rootScope": {"kind": "resourceGroup", "subscriptionId": "0000", "name": "rg1"},
+//	  "scopes": [{"provider": "Microsoft.Network", "types": ["virtualNetworks"], "names": ["vnet1"]}],
+//	  "typeString": "Microsoft.Network/virtualNetworks",
+//	  "kind": "topLevel"
+//	}
+type Components struct {
+	RootScope RootScopeComponents `json:"rootScope"`
+
+	// Scopes are the scopes below the root scope, from the outermost one to the route scope.
+	Scopes []ScopeComponents `json:"scopes,omitempty"`
+
+	// TypeString is the type string of the resource id. It is only informative, and is ignored when converting back to a resource id.
+	TypeString string `json:"typeString,omitempty"`
+
+	// Kind is the kind of the resource id, in lower camel case (e.g. "topLevel"). It is only informative, and is ignored when converting
+	// back to a resource id.
+	Kind string `json:"kind,omitempty"`
+}
+
+// RootScopeComponents is the structured representation of a root scope.
+type RootScopeComponents struct {
+	// Kind is one of "tenant", "subscription", "resourceGroup" and "managementGroup".
+	Kind string `json:"kind"`
+
+	// SubscriptionId is set for the subscription and the resource group.
+	SubscriptionId string `json:"subscriptionId,omitempty"`
+
+	// Name is set for the resource group and the management group.
+	Name string `json:"name,omitempty"`
+
+	// Types and Names are the root scope level resource types and names.
+	Types []string `json:"types,omitempty"`
+	Names []string `json:"names,omitempty"`
+}
+
+// ScopeComponents is the structured representation of a scope within a resource id, i.e. the part led by "/providers/".
+type ScopeComponents struct {
+	Provider string   `json:"provider"`
+	Types    []string `json:"types"`
+	Names    []string `json:"names"`
+}
+
+// kindName returns the name of the kind in lower camel case, e.g. "resourceGroup".
+func kindName(k Kind) string {
+	s := k.String()
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// ComponentsOf returns the structured representation of the resource id.
+func ComponentsOf(id ResourceId) Components {
+	c := Components{
+		TypeString: id.TypeString(),
+		Kind:       kindName(KindOf(id)),
+	}
+	traverseScopes(id, func(id ResourceId) {
+		switch id := id.(type) {
+		case *TenantId:
+			c.RootScope = RootScopeComponents{Kind: kindName(KindTenant)}
+		case *SubscriptionId:
+			c.RootScope = RootScopeComponents{
+				Kind:           kindName(KindSubscription),
+				SubscriptionId: id.Id,
+				Types:          append([]string(nil), id.AttrTypes...),
+				Names:          append([]string(nil), id.AttrNames...),
+			}
+		case *ResourceGroup:
+			c.RootScope = RootScopeComponents{
+				Kind:           kindName(KindResourceGroup),
+				SubscriptionId: id.SubscriptionId,
+				Name:           id.Name,
+				Types:          append([]string(nil), id.AttrTypes...),
+				Names:          append([]string(nil), id.AttrNames...),
+			}
+		case *ManagementGroup:
+			c.RootScope = RootScopeComponents{
+				Kind:  kindName(KindManagementGroup),
+				Name:  id.Name,
+				Types: append([]string(nil), id.AttrTypes...),
+				Names: append([]string(nil), id.AttrNames...),
+			}
+		case *ScopedResourceId:
+			c.Scopes = append(c.Scopes, ScopeComponents{
+				Provider: id.AttrProvider,
+				Types:    append([]string{}, id.AttrTypes...),
+				Names:    append([]string{}, id.AttrNames...),
+			})
+		}
+	})
+	return c
+}
+
+// ResourceId converts the structured representation back to a resource id.
+func (c Components) ResourceId() (ResourceId, error) {
+	var id ResourceId
+	var err error
+	root := c.RootScope
+	switch root.Kind {
+	case kindName(KindTenant):
+		if len(root.Types) != 0 {
+			return nil, fmt.Errorf("root scope level resource is not supported under the tenant")
+		}
+		id = &TenantId{}
+	case kindName(KindSubscription):
+		id, err = NewSubscriptionId(root.SubscriptionId)
+	case kindName(KindResourceGroup):
+		id, err = NewResourceGroup(root.SubscriptionId, root.Name)
+	case kindName(KindManagementGroup):
+		id, err = NewManagementGroup(root.Name)
+	default:
+		return nil, fmt.Errorf("unknown root scope kind %q", root.Kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(root.Types) != len(root.Names) {
+		return nil, fmt.Errorf("the number of types (%d) and names (%d) mismatch", len(root.Types), len(root.Names))
+	}
+	for i := range root.Types {
+		if id, err = Child(id, root.Types[i], root.Names[i]); err != nil {
+			return nil, err
+		}
+	}
+	for i, scope := range c.Scopes {
+		if id, err = Extend(id, scope.Provider, scope.Types, scope.Names); err != nil {
+			return nil, fmt.Errorf("scope %d: %v", i, err)
+		}
+	}
+	return id, nil
+}
+
+// MarshalComponents marshals the resource id into the JSON of its structured representation, as defined by ComponentsJSONSchema.
+func MarshalComponents(id ResourceId) ([]byte, error) {
+	return json.Marshal(ComponentsOf(id))
+}
+
+// UnmarshalComponents unmarshals the JSON of the structured representation, as defined by ComponentsJSONSchema, into a resource id.
+func UnmarshalComponents(b []byte) (ResourceId, error) {
+	var c Components
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("unmarshaling components: %v", err)
+	}
+	return c.ResourceId()
+}
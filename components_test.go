@@ -0,0 +1,119 @@
+package armid
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalComponents(t *testing.T) {
+	cases := []struct {
+		name   string
+		input  string
+		expect string
+	}{
+		{
+			name:   "Tenant",
+			input:  "/",
+			expect: `{"rootScope":{"kind":"tenant"},"kind":"tenant"}`,
+		},
+		{
+			name:   "Resource group",
+			input:  "/subscriptions/sub1/resourceGroups/rg1",
+			expect: `{"rootScope":{"kind":"resourceGroup","subscriptionId":"sub1","name":"rg1"},"typeString":"Microsoft.Resources/subscriptions/resourceGroups","kind":"resourceGroup"}`,
+		},
+		{
+			name:   "Root scope level resource",
+			input:  "/subscriptions/sub1/tagNames/tag1",
+			expect: `{"rootScope":{"kind":"subscription","subscriptionId":"sub1","types":["tagNames"],"names":["tag1"]},"typeString":"Microsoft.Resources/subscriptions/tagNames","kind":"rootChild"}`,
+		},
+		{
+			name:   "Provider",
+			input:  "/subscriptions/sub1/providers/Microsoft.Foo",
+			expect: `{"rootScope":{"kind":"subscription","subscriptionId":"sub1"},"scopes":[{"provider":"Microsoft.Foo","types":[],"names":[]}],"typeString":"Microsoft.Foo","kind":"provider"}`,
+		},
+		{
+			name:  "Extension under management group",
+			input: "/providers/Microsoft.Management/managementGroups/mg1/providers/Microsoft.Foo/foos/foo1/bars/bar1/providers/Microsoft.Authorization/locks/lock1",
+			expect: `{"rootScope":{"kind":"managementGroup","name":"mg1"},"scopes":[` +
+				`{"provider":"Microsoft.Foo","types":["foos","bars"],"names":["foo1","bar1"]},` +
+				`{"provider":"Microsoft.Authorization","types":["locks"],"names":["lock1"]}],` +
+				`"typeString":"Microsoft.Authorization/locks","kind":"extension"}`,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := ParseResourceId(tt.input)
+			require.NoError(t, err)
+			b, err := MarshalComponents(id)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.expect, string(b))
+			out, err := UnmarshalComponents(b)
+			require.NoError(t, err)
+			require.Equal(t, tt.input, out.String())
+			require.True(t, id.Equal(out))
+		})
+	}
+}
+
+func TestUnmarshalComponents_Error(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{
+			name:  "Invalid JSON",
+			input: `[]`,
+			err:   "unmarshaling components: json: cannot unmarshal array into Go value of type armid.Components",
+		},
+		{
+			name:  "Unknown root scope",
+			input: `{"rootScope":{"kind":"foo"}}`,
+			err:   `unknown root scope kind "foo"`,
+		},
+		{
+			name:  "Missing resource group name",
+			input: `{"rootScope":{"kind":"resourceGroup","subscriptionId":"sub1"}}`,
+			err:   "empty resource group name",
+		},
+		{
+			name:  "Root scope level resource under tenant",
+			input: `{"rootScope":{"kind":"tenant","types":["foos"],"names":["foo1"]}}`,
+			err:   "root scope level resource is not supported under the tenant",
+		},
+		{
+			name:  "Mismatched root scope types and names",
+			input: `{"rootScope":{"kind":"subscription","subscriptionId":"sub1","types":["foos"]}}`,
+			err:   "the number of types (1) and names (0) mismatch",
+		},
+		{
+			name:  "Provider in the middle",
+			input: `{"rootScope":{"kind":"subscription","subscriptionId":"sub1"},"scopes":[{"provider":"Microsoft.Foo","types":[],"names":[]},{"provider":"Microsoft.Bar","types":["bars"],"names":["bar1"]}]}`,
+			err:   `scope 1: provider "Microsoft.Foo" has no resource type`,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := UnmarshalComponents([]byte(tt.input))
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestComponentsJSONSchema(t *testing.T) {
+	var schema struct {
+		Properties struct {
+			Kind struct {
+				Enum []string `json:"enum"`
+			} `json:"kind"`
+		} `json:"properties"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(ComponentsJSONSchema), &schema))
+	var kinds []string
+	for k := KindTenant; k <= KindLocation; k++ {
+		kinds = append(kinds, kindName(k))
+	}
+	require.ElementsMatch(t, kinds, schema.Properties.Kind.Enum)
+}
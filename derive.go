@@ -0,0 +1,95 @@
+package armid
+
+import (
+	"fmt"
+)
+
+// Child returns a new resource id that appends the resource type and name to the resource id, within the same provider (or root scope).
+// For root scopes, this builds a root scope level resource, which is not supported under the tenant.
+func Child(id ResourceId, typ, name string) (ResourceId, error) {
+	if _, ok := id.(*TenantId); ok {
+		return nil, fmt.Errorf("root scope level resource is not supported under the tenant")
+	}
+	if err := validateTypesAndNames([]string{typ}, []string{name}); err != nil {
+		return nil, err
+	}
+	switch out := id.Clone().(type) {
+	case *SubscriptionId:
+		out.AttrTypes = append(out.AttrTypes, typ)
+		out.AttrNames = append(out.AttrNames, name)
+		return out, nil
+	case *ResourceGroup:
+		out.AttrTypes = append(out.AttrTypes, typ)
+		out.AttrNames = append(out.AttrNames, name)
+		return out, nil
+	case *ManagementGroup:
+		out.AttrTypes = append(out.AttrTypes, typ)
+		out.AttrNames = append(out.AttrNames, name)
+		return out, nil
+	case *ScopedResourceId:
+		out.AttrTypes = append(out.AttrTypes, typ)
+		out.AttrNames = append(out.AttrNames, name)
+		return out, nil
+	default:
+		return nil, fmt.Errorf("unsupported resource id type %T", id)
+	}
+}
+
+// Sibling returns a new resource id that replaces the last name of the resource id, i.e. the last element of Names().
+func Sibling(id ResourceId, name string) (ResourceId, error) {
+	n := len(id.Names())
+	if n == 0 {
+		return nil, fmt.Errorf("%q has no resource name", id.String())
+	}
+	return WithNameAt(id, n-1, name)
+}
+
+// WithNameAt returns a new resource id that replaces the name at the index of Names() of the resource id.
+func WithNameAt(id ResourceId, index int, name string) (ResourceId, error) {
+	names := id.Names()
+	if index < 0 || index >= len(names) {
+		return nil, fmt.Errorf("name index %d is out of range [0, %d) for %q", index, len(names), id.String())
+	}
+	if err := validateSegment("resource name", name); err != nil {
+		return nil, err
+	}
+	switch out := id.Clone().(type) {
+	case *SubscriptionId:
+		if index == 0 {
+			out.Id = name
+		} else {
+			out.AttrNames[index-1] = name
+		}
+		return out, nil
+	case *ResourceGroup:
+		switch index {
+		case 0:
+			out.SubscriptionId = name
+		case 1:
+			out.Name = name
+		default:
+			out.AttrNames[index-2] = name
+		}
+		return out, nil
+	case *ManagementGroup:
+		if index == 0 {
+			out.Name = name
+		} else {
+			out.AttrNames[index-1] = name
+		}
+		return out, nil
+	case *ScopedResourceId:
+		out.AttrNames[index] = name
+		return out, nil
+	default:
+		return nil, fmt.Errorf("unsupported resource id type %T", id)
+	}
+}
+
+// Extend returns a new extension resource id that is scoped to the resource id.
+func Extend(id ResourceId, provider string, types, names []string) (ResourceId, error) {
+	if sid, ok := id.(*ScopedResourceId); ok && len(sid.AttrTypes) == 0 {
+		return nil, fmt.Errorf("provider %q has no resource type", sid.AttrProvider)
+	}
+	return NewScopedResourceId(id.Clone(), provider, types, names)
+}
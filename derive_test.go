@@ -0,0 +1,201 @@
+package armid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChild(t *testing.T) {
+	cases := []struct {
+		name   string
+		input  string
+		typ    string
+		rname  string
+		expect string
+		err    string
+	}{
+		{
+			name:   "Scoped resource",
+			input:  "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1",
+			typ:    "subnets",
+			rname:  "subnet1",
+			expect: "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet1",
+		},
+		{
+			name:   "Subscription",
+			input:  "/subscriptions/sub1",
+			typ:    "tagNames",
+			rname:  "tag1",
+			expect: "/subscriptions/sub1/tagNames/tag1",
+		},
+		{
+			name:   "Resource group",
+			input:  "/subscriptions/sub1/resourceGroups/rg1",
+			typ:    "foos",
+			rname:  "foo1",
+			expect: "/subscriptions/sub1/resourceGroups/rg1/foos/foo1",
+		},
+		{
+			name:   "Management group",
+			input:  "/providers/Microsoft.Management/managementGroups/mg1/foos/foo1",
+			typ:    "bars",
+			rname:  "bar1",
+			expect: "/providers/Microsoft.Management/managementGroups/mg1/foos/foo1/bars/bar1",
+		},
+		{
+			name:  "Tenant",
+			input: "/",
+			typ:   "foos",
+			rname: "foo1",
+			err:   "root scope level resource is not supported under the tenant",
+		},
+		{
+			name:  "Invalid name",
+			input: "/subscriptions/sub1",
+			typ:   "foos",
+			rname: "",
+			err:   "empty resource name",
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := ParseResourceId(tt.input)
+			require.NoError(t, err)
+			out, err := Child(id, tt.typ, tt.rname)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, out.String())
+			require.Equal(t, tt.input, id.String())
+		})
+	}
+}
+
+func TestWithNameAt(t *testing.T) {
+	cases := []struct {
+		name   string
+		input  string
+		index  int
+		rname  string
+		expect string
+		err    string
+	}{
+		{
+			name:   "Subscription id",
+			input:  "/subscriptions/sub1/resourceGroups/rg1",
+			index:  0,
+			rname:  "sub2",
+			expect: "/subscriptions/sub2/resourceGroups/rg1",
+		},
+		{
+			name:   "Root scope level resource",
+			input:  "/subscriptions/sub1/resourceGroups/rg1/foos/foo1",
+			index:  2,
+			rname:  "foo2",
+			expect: "/subscriptions/sub1/resourceGroups/rg1/foos/foo2",
+		},
+		{
+			name:   "Management group",
+			input:  "/providers/Microsoft.Management/managementGroups/mg1",
+			index:  0,
+			rname:  "mg2",
+			expect: "/providers/Microsoft.Management/managementGroups/mg2",
+		},
+		{
+			name:   "Scoped resource",
+			input:  "/subscriptions/sub1/providers/Microsoft.Foo/foos/foo1/bars/bar1",
+			index:  0,
+			rname:  "foo2",
+			expect: "/subscriptions/sub1/providers/Microsoft.Foo/foos/foo2/bars/bar1",
+		},
+		{
+			name:  "Out of range",
+			input: "/subscriptions/sub1/providers/Microsoft.Foo/foos/foo1",
+			index: 1,
+			rname: "foo2",
+			err:   `name index 1 is out of range [0, 1) for "/subscriptions/sub1/providers/Microsoft.Foo/foos/foo1"`,
+		},
+		{
+			name:  "Invalid name",
+			input: "/subscriptions/sub1",
+			index: 0,
+			rname: "a/b",
+			err:   `resource name "a/b" contains "/"`,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := ParseResourceId(tt.input)
+			require.NoError(t, err)
+			out, err := WithNameAt(id, tt.index, tt.rname)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, out.String())
+			require.Equal(t, tt.input, id.String())
+		})
+	}
+}
+
+func TestSibling(t *testing.T) {
+	id, err := ParseResourceId("/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet1")
+	require.NoError(t, err)
+	out, err := Sibling(id, "subnet2")
+	require.NoError(t, err)
+	require.Equal(t, "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet2", out.String())
+	require.Equal(t, "subnet1", id.Names()[1])
+
+	rg, err := ParseResourceId("/subscriptions/sub1/resourceGroups/rg1")
+	require.NoError(t, err)
+	out, err = Sibling(rg, "rg2")
+	require.NoError(t, err)
+	require.Equal(t, "/subscriptions/sub1/resourceGroups/rg2", out.String())
+
+	_, err = Sibling(&TenantId{}, "foo")
+	require.EqualError(t, err, `"/" has no resource name`)
+
+	p, err := ParseResourceId("/subscriptions/sub1/providers/Microsoft.Foo")
+	require.NoError(t, err)
+	_, err = Sibling(p, "foo")
+	require.EqualError(t, err, `"/subscriptions/sub1/providers/Microsoft.Foo" has no resource name`)
+}
+
+func TestExtend(t *testing.T) {
+	id, err := ParseResourceId("/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1")
+	require.NoError(t, err)
+	types, names := []string{"locks"}, []string{"lock1"}
+	out, err := Extend(id, "Microsoft.Authorization", types, names)
+	require.NoError(t, err)
+	names[0] = "changed"
+	require.Equal(t, "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/providers/Microsoft.Authorization/locks/lock1", out.String())
+	require.Equal(t, id, out.ParentScope())
+	require.NotSame(t, id, out.ParentScope())
+
+	out, err = Extend(&TenantId{}, "Microsoft.Foo", []string{"foos"}, []string{"foo1"})
+	require.NoError(t, err)
+	require.Equal(t, "/providers/Microsoft.Foo/foos/foo1", out.String())
+
+	p, err := ParseResourceId("/subscriptions/sub1/providers/Microsoft.Foo")
+	require.NoError(t, err)
+	_, err = Extend(p, "Microsoft.Bar", []string{"bars"}, []string{"bar1"})
+	require.EqualError(t, err, `provider "Microsoft.Foo" has no resource type`)
+	_, err = Extend(id, "Microsoft.Bar", []string{"bars"}, nil)
+	require.EqualError(t, err, "the number of types (1) and names (0) mismatch")
+}
+
+func TestDeriveNoAlias(t *testing.T) {
+	id, err := ParseResourceId("/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Foo/foos/foo1/bars/bar1")
+	require.NoError(t, err)
+	// Parent shares the underlying array, deriving from it must not overwrite the original id.
+	parent := id.Parent()
+	_, err = Child(parent, "bazs", "baz1")
+	require.NoError(t, err)
+	_, err = Sibling(parent, "foo2")
+	require.NoError(t, err)
+	require.Equal(t, "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Foo/foos/foo1/bars/bar1", id.String())
+}
@@ -0,0 +1,130 @@
+package armid
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ParseErrorKind classifies the reason of a ParseError.
+type ParseErrorKind int
+
+const (
+	// ParseErrorMissingLeadingSlash indicates the id doesn't start with "/".
+	ParseErrorMissingLeadingSlash ParseErrorKind = iota + 1
+	// ParseErrorEmptySegment indicates the id contains an empty segment, e.g. "//" or a trailing "/".
+	ParseErrorEmptySegment
+	// ParseErrorMissingResourceName indicates a resource type segment is not followed by its name.
+	ParseErrorMissingResourceName
+	// ParseErrorMissingProviderNamespace indicates a "providers" segment is not followed by the provider namespace.
+	ParseErrorMissingProviderNamespace
+	// ParseErrorInvalidScopeSeparator indicates the scopes are not split by "/providers/".
+	ParseErrorInvalidScopeSeparator
+	// ParseErrorUnsupportedRootScopeResource indicates there are root scope level resources defined under a root scope that doesn't support them (i.e. the tenant).
+	ParseErrorUnsupportedRootScopeResource
+)
+
+// Sentinel errors for each ParseErrorKind, which can be matched against a *ParseError via errors.Is.
+var (
+	ErrMissingLeadingSlash          = errors.New(`id should start with "/"`)
+	ErrEmptySegment                 = errors.New("empty segment")
+	ErrMissingResourceName          = errors.New("missing resource type name")
+	ErrMissingProviderNamespace     = errors.New("missing provider namespace segment")
+	ErrInvalidScopeSeparator        = errors.New(`scopes should be split by "/providers/"`)
+	ErrUnsupportedRootScopeResource = errors.New("unsupported root scope level resource")
+)
+
+func (k ParseErrorKind) String() string {
+	switch k {
+	case ParseErrorMissingLeadingSlash:
+		return "MissingLeadingSlash"
+	case ParseErrorEmptySegment:
+		return "EmptySegment"
+	case ParseErrorMissingResourceName:
+		return "MissingResourceName"
+	case ParseErrorMissingProviderNamespace:
+		return "MissingProviderNamespace"
+	case ParseErrorInvalidScopeSeparator:
+		return "InvalidScopeSeparator"
+	case ParseErrorUnsupportedRootScopeResource:
+		return "UnsupportedRootScopeResource"
+	default:
+		return fmt.Sprintf("ParseErrorKind(%d)", int(k))
+	}
+}
+
+func (k ParseErrorKind) sentinel() error {
+	switch k {
+	case ParseErrorMissingLeadingSlash:
+		return ErrMissingLeadingSlash
+	case ParseErrorEmptySegment:
+		return ErrEmptySegment
+	case ParseErrorMissingResourceName:
+		return ErrMissingResourceName
+	case ParseErrorMissingProviderNamespace:
+		return ErrMissingProviderNamespace
+	case ParseErrorInvalidScopeSeparator:
+		return ErrInvalidScopeSeparator
+	case ParseErrorUnsupportedRootScopeResource:
+		return ErrUnsupportedRootScopeResource
+	default:
+		return nil
+	}
+}
+
+// ParseError is the error returned by ParseResourceId when the input is not a valid resource id.
+type ParseError struct {
+	// Kind is the reason of this error.
+	Kind ParseErrorKind
+
+	// Input is the string being parsed.
+	Input string
+
+	// Offset is the byte offset of the offending segment in Input.
+	Offset int
+
+	// SegmentIndex is the index of the offending segment, counting from the first segment following the leading "/".
+	// It is -1 if the error is not about a specific segment.
+	SegmentIndex int
+
+	// Segment is the offending segment.
+	Segment string
+
+	// Partial is the resource id that has been successfully parsed before the error occurs. It can be nil.
+	Partial ResourceId
+
+	msg string
+}
+
+func (e *ParseError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the sentinel error of the error kind.
+func (e *ParseError) Unwrap() error {
+	return e.Kind.sentinel()
+}
+
+func newParseError(input string, segs []string, idx int, kind ParseErrorKind, partial ResourceId, msg string) *ParseError {
+	err := &ParseError{
+		Kind:         kind,
+		Input:        input,
+		SegmentIndex: -1,
+		Partial:      partial,
+		msg:          msg,
+	}
+	if idx >= 0 && idx < len(segs) {
+		err.SegmentIndex = idx
+		err.Segment = segs[idx]
+		err.Offset = segmentOffset(segs, idx)
+	}
+	return err
+}
+
+// segmentOffset returns the byte offset of the idx-th segment in the id, where the segments are split from the id with the leading "/" trimmed.
+func segmentOffset(segs []string, idx int) int {
+	offset := 1
+	for _, seg := range segs[:idx] {
+		offset += len(seg) + 1
+	}
+	return offset
+}
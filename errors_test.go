@@ -0,0 +1,113 @@
+package armid
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseError(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		kind     ParseErrorKind
+		sentinel error
+		offset   int
+		segIdx   int
+		segment  string
+		partial  ResourceId
+	}{
+		{
+			name:     "missing leading slash",
+			input:    "subscriptions/sub1",
+			kind:     ParseErrorMissingLeadingSlash,
+			sentinel: ErrMissingLeadingSlash,
+			offset:   0,
+			segIdx:   -1,
+		},
+		{
+			name:     "empty segment",
+			input:    "/subscriptions//resourceGroups/rg1",
+			kind:     ParseErrorEmptySegment,
+			sentinel: ErrEmptySegment,
+			offset:   15,
+			segIdx:   1,
+			segment:  "",
+		},
+		{
+			name:     "missing root scope level resource name",
+			input:    "/subscriptions/sub1/tagNames",
+			kind:     ParseErrorMissingResourceName,
+			sentinel: ErrMissingResourceName,
+			offset:   20,
+			segIdx:   2,
+			segment:  "tagNames",
+			partial:  &SubscriptionId{Id: "sub1"},
+		},
+		{
+			name:     "missing resource name",
+			input:    "/subscriptions/sub1/providers/Microsoft.Foo/foos/foo1/bars",
+			kind:     ParseErrorMissingResourceName,
+			sentinel: ErrMissingResourceName,
+			offset:   54,
+			segIdx:   6,
+			segment:  "bars",
+			partial:  &SubscriptionId{Id: "sub1"},
+		},
+		{
+			name:     "missing provider namespace",
+			input:    "/subscriptions/sub1/providers/Microsoft.Foo/foos/foo1/providers",
+			kind:     ParseErrorMissingProviderNamespace,
+			sentinel: ErrMissingProviderNamespace,
+			offset:   54,
+			segIdx:   6,
+			segment:  "providers",
+			partial: &ScopedResourceId{
+				AttrParentScope: &SubscriptionId{Id: "sub1"},
+				AttrProvider:    "Microsoft.Foo",
+				AttrTypes:       []string{"foos"},
+				AttrNames:       []string{"foo1"},
+			},
+		},
+		{
+			name:     "missing root scope level resource name after another one",
+			input:    "/subscriptions/sub1/tagNames/name1/foo",
+			kind:     ParseErrorMissingResourceName,
+			sentinel: ErrMissingResourceName,
+			offset:   35,
+			segIdx:   4,
+			segment:  "foo",
+			partial:  &SubscriptionId{Id: "sub1"},
+		},
+		{
+			name:     "root scope level resource under tenant",
+			input:    "/foos/foo1",
+			kind:     ParseErrorUnsupportedRootScopeResource,
+			sentinel: ErrUnsupportedRootScopeResource,
+			offset:   1,
+			segIdx:   0,
+			segment:  "foos",
+			partial:  &TenantId{},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseResourceId(tt.input)
+			require.Error(t, err)
+			var perr *ParseError
+			require.True(t, errors.As(err, &perr))
+			require.True(t, errors.Is(err, tt.sentinel))
+			require.Equal(t, tt.kind, perr.Kind)
+			require.Equal(t, tt.input, perr.Input)
+			require.Equal(t, tt.offset, perr.Offset)
+			require.Equal(t, tt.segIdx, perr.SegmentIndex)
+			require.Equal(t, tt.segment, perr.Segment)
+			require.Equal(t, tt.partial, perr.Partial)
+			if perr.SegmentIndex >= 0 {
+				require.Equal(t, tt.segment, tt.input[perr.Offset:perr.Offset+len(perr.Segment)])
+			}
+		})
+	}
+}
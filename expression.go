@@ -0,0 +1,443 @@
+package armid
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatTemplateExpression formats the resource id into an ARM template expression that evaluates to the id, e.g.
+// "resourceId('sub1', 'rg1', 'Microsoft.Network/virtualNetworks/subnets', 'vnet1', 'subnet1')".
+// The function is chosen based on the parent scope of the id: resourceId, subscriptionResourceId, managementGroupResourceId,
+// tenantResourceId, or extensionResourceId for a resource scoped to another resource.
+//
+// If relative is true, the subscription id, resource group name and management group name are omitted, so that
+// the expression is relative to the deployment scope. The same expression is valid in Bicep.
+func FormatTemplateExpression(id ResourceId, relative bool) (string, error) {
+	switch id := id.(type) {
+	case *TenantId:
+		return "", fmt.Errorf("tenant scope can't be expressed as a template expression")
+	case *SubscriptionId:
+		if len(id.AttrTypes) != 0 {
+			return "", fmt.Errorf("root scope level resource %q can't be expressed as a template expression", id.String())
+		}
+		if relative {
+			return "subscription().id", nil
+		}
+		return quoteTemplateString(id.String()), nil
+	case *ResourceGroup:
+		if len(id.AttrTypes) != 0 {
+			return "", fmt.Errorf("root scope level resource %q can't be expressed as a template expression", id.String())
+		}
+		if relative {
+			return "resourceGroup().id", nil
+		}
+		return formatTemplateFunction("subscriptionResourceId", []string{id.SubscriptionId, "Microsoft.Resources/resourceGroups", id.Name}), nil
+	case *ManagementGroup:
+		if len(id.AttrTypes) != 0 {
+			return "", fmt.Errorf("root scope level resource %q can't be expressed as a template expression", id.String())
+		}
+		if relative {
+			return "managementGroup().id", nil
+		}
+		return formatTemplateFunction("tenantResourceId", []string{"Microsoft.Management/managementGroups", id.Name}), nil
+	case *ScopedResourceId:
+		if len(id.AttrTypes) == 0 {
+			return "", fmt.Errorf("provider level id %q can't be expressed as a template expression", id.String())
+		}
+		args := append([]string{id.TypeString()}, id.AttrNames...)
+		if !isBareRootScope(id.AttrParentScope) {
+			pexpr, err := FormatTemplateExpression(id.AttrParentScope, relative)
+			if err != nil {
+				return "", fmt.Errorf("formatting the parent scope: %v", err)
+			}
+			return "extensionResourceId(" + pexpr + ", " + strings.Join(quoteTemplateStrings(args), ", ") + ")", nil
+		}
+		switch pid := id.AttrParentScope.(type) {
+		case *TenantId:
+			return formatTemplateFunction("tenantResourceId", args), nil
+		case *SubscriptionId:
+			if !relative {
+				args = append([]string{pid.Id}, args...)
+			}
+			return formatTemplateFunction("subscriptionResourceId", args), nil
+		case *ResourceGroup:
+			if !relative {
+				args = append([]string{pid.SubscriptionId, pid.Name}, args...)
+			}
+			return formatTemplateFunction("resourceId", args), nil
+		case *ManagementGroup:
+			if !relative {
+				args = append([]string{pid.Name}, args...)
+			}
+			return formatTemplateFunction("managementGroupResourceId", args), nil
+		}
+	}
+	return "", fmt.Errorf("unsupported resource id type %T", id)
+}
+
+// isBareRootScope tells whether the id is a root scope without any root scope level resource types.
+func isBareRootScope(id ResourceId) bool {
+	switch id := id.(type) {
+	case *TenantId:
+		return true
+	case *SubscriptionId:
+		return len(id.AttrTypes) == 0
+	case *ResourceGroup:
+		return len(id.AttrTypes) == 0
+	case *ManagementGroup:
+		return len(id.AttrTypes) == 0
+	}
+	return false
+}
+
+func formatTemplateFunction(name string, args []string) string {
+	return name + "(" + strings.Join(quoteTemplateStrings(args), ", ") + ")"
+}
+
+func quoteTemplateStrings(l []string) []string {
+	out := make([]string, 0, len(l))
+	for _, v := range l {
+		out = append(out, quoteTemplateString(v))
+	}
+	return out
+}
+
+func quoteTemplateString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// ParseTemplateExpression evaluates an ARM template expression built from the resourceId, subscriptionResourceId, managementGroupResourceId,
+// tenantResourceId and extensionResourceId functions into a ResourceId. The expression can optionally be enclosed in "[" and "]".
+// All the function arguments must be string literals, or a nested call of these functions.
+//
+// The scope is the deployment scope, which is used to evaluate the relative expressions (i.e. the ones omitting the subscription id,
+// resource group name or management group name), as well as the resourceGroup().id, subscription().id and managementGroup().id.
+// It can be nil if the expression is not relative.
+func ParseTemplateExpression(expr string, scope RootScope) (ResourceId, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "[") && strings.HasSuffix(expr, "]") {
+		expr = expr[1 : len(expr)-1]
+	}
+	tokens, err := tokenizeTemplateExpression(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &templateExpressionParser{tokens: tokens, scope: scope}
+	v, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if tk := p.peek(); tk.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %s at offset %d", tk, tk.offset)
+	}
+	return ParseResourceId(v)
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenLParen
+	tokenRParen
+	tokenComma
+	tokenDot
+)
+
+type token struct {
+	kind   tokenKind
+	value  string
+	offset int
+}
+
+func (tk token) String() string {
+	switch tk.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return fmt.Sprintf("string %s", quoteTemplateString(tk.value))
+	default:
+		return fmt.Sprintf("%q", tk.value)
+	}
+}
+
+func tokenizeTemplateExpression(expr string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, value: "(", offset: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, value: ")", offset: i})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokenComma, value: ",", offset: i})
+			i++
+		case c == '.':
+			tokens = append(tokens, token{kind: tokenDot, value: ".", offset: i})
+			i++
+		case c == '\'':
+			start := i
+			var sb strings.Builder
+			i++
+			for {
+				if i >= len(expr) {
+					return nil, fmt.Errorf("unterminated string starting at offset %d", start)
+				}
+				if expr[i] == '\'' {
+					if i+1 < len(expr) && expr[i+1] == '\'' {
+						sb.WriteByte('\'')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				sb.WriteByte(expr[i])
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenString, value: sb.String(), offset: start})
+		case c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z'):
+			start := i
+			for i < len(expr) && (expr[i] == '_' || ('a' <= expr[i] && expr[i] <= 'z') || ('A' <= expr[i] && expr[i] <= 'Z') || ('0' <= expr[i] && expr[i] <= '9')) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, value: expr[start:i], offset: start})
+		default:
+			return nil, fmt.Errorf("unexpected character %q at offset %d", c, i)
+		}
+	}
+	tokens = append(tokens, token{kind: tokenEOF, offset: len(expr)})
+	return tokens, nil
+}
+
+type templateExpressionParser struct {
+	tokens []token
+	scope  RootScope
+}
+
+func (p *templateExpressionParser) peek() token {
+	return p.tokens[0]
+}
+
+func (p *templateExpressionParser) next() token {
+	tk := p.tokens[0]
+	if tk.kind != tokenEOF {
+		p.tokens = p.tokens[1:]
+	}
+	return tk
+}
+
+func (p *templateExpressionParser) expect(kind tokenKind, what string) (token, error) {
+	tk := p.next()
+	if tk.kind != kind {
+		return tk, fmt.Errorf("expect %s, got %s at offset %d", what, tk, tk.offset)
+	}
+	return tk, nil
+}
+
+// parseExpr parses an expression and evaluates it into a string.
+func (p *templateExpressionParser) parseExpr() (string, error) {
+	tk := p.next()
+	switch tk.kind {
+	case tokenString:
+		return tk.value, nil
+	case tokenIdent:
+		if _, err := p.expect(tokenLParen, `"("`); err != nil {
+			return "", err
+		}
+		var args []string
+		if p.peek().kind == tokenRParen {
+			p.next()
+		} else {
+			for {
+				arg, err := p.parseExpr()
+				if err != nil {
+					return "", err
+				}
+				args = append(args, arg)
+				sep := p.next()
+				if sep.kind == tokenRParen {
+					break
+				}
+				if sep.kind != tokenComma {
+					return "", fmt.Errorf(`expect "," or ")", got %s at offset %d`, sep, sep.offset)
+				}
+			}
+		}
+		return p.evalFunction(tk, args)
+	default:
+		return "", fmt.Errorf("expect a string or a function call, got %s at offset %d", tk, tk.offset)
+	}
+}
+
+func (p *templateExpressionParser) evalFunction(fn token, args []string) (string, error) {
+	switch strings.ToLower(fn.value) {
+	case "resourcegroup", "subscription", "managementgroup":
+		if len(args) != 0 {
+			return "", fmt.Errorf("%s() with arguments is not supported", fn.value)
+		}
+		if _, err := p.expect(tokenDot, `"."`); err != nil {
+			return "", err
+		}
+		prop, err := p.expect(tokenIdent, "property name")
+		if err != nil {
+			return "", err
+		}
+		if !strings.EqualFold(prop.value, "id") {
+			return "", fmt.Errorf("property %q of %s() is not supported", prop.value, fn.value)
+		}
+		switch strings.ToLower(fn.value) {
+		case "resourcegroup":
+			if rg, ok := p.scope.(*ResourceGroup); ok {
+				return (&ResourceGroup{SubscriptionId: rg.SubscriptionId, Name: rg.Name}).String(), nil
+			}
+		case "subscription":
+			switch scope := p.scope.(type) {
+			case *SubscriptionId:
+				return (&SubscriptionId{Id: scope.Id}).String(), nil
+			case *ResourceGroup:
+				return (&SubscriptionId{Id: scope.SubscriptionId}).String(), nil
+			}
+		case "managementgroup":
+			if mg, ok := p.scope.(*ManagementGroup); ok {
+				return (&ManagementGroup{Name: mg.Name}).String(), nil
+			}
+		}
+		return "", fmt.Errorf("%s().id can't be evaluated against the deployment scope %s", fn.value, scopeDescription(p.scope))
+	case "resourceid":
+		prefix, typ, names, err := p.splitResourceFunctionArgs(fn, args, 2)
+		if err != nil {
+			return "", err
+		}
+		var sub, rg string
+		switch len(prefix) {
+		case 2:
+			sub, rg = prefix[0], prefix[1]
+		case 1:
+			rg = prefix[0]
+			switch scope := p.scope.(type) {
+			case *SubscriptionId:
+				sub = scope.Id
+			case *ResourceGroup:
+				sub = scope.SubscriptionId
+			default:
+				return "", fmt.Errorf("the subscription id of %s() can't be evaluated against the deployment scope %s", fn.value, scopeDescription(p.scope))
+			}
+		case 0:
+			scope, ok := p.scope.(*ResourceGroup)
+			if !ok {
+				return "", fmt.Errorf("the resource group of %s() can't be evaluated against the deployment scope %s", fn.value, scopeDescription(p.scope))
+			}
+			sub, rg = scope.SubscriptionId, scope.Name
+		}
+		return buildTemplateResourceId((&ResourceGroup{SubscriptionId: sub, Name: rg}).String(), typ, names), nil
+	case "subscriptionresourceid":
+		prefix, typ, names, err := p.splitResourceFunctionArgs(fn, args, 1)
+		if err != nil {
+			return "", err
+		}
+		var sub string
+		if len(prefix) == 1 {
+			sub = prefix[0]
+		} else {
+			switch scope := p.scope.(type) {
+			case *SubscriptionId:
+				sub = scope.Id
+			case *ResourceGroup:
+				sub = scope.SubscriptionId
+			default:
+				return "", fmt.Errorf("the subscription id of %s() can't be evaluated against the deployment scope %s", fn.value, scopeDescription(p.scope))
+			}
+		}
+		if strings.EqualFold(typ, "Microsoft.Resources/resourceGroups") && len(names) == 1 {
+			return (&ResourceGroup{SubscriptionId: sub, Name: names[0]}).String(), nil
+		}
+		return buildTemplateResourceId((&SubscriptionId{Id: sub}).String(), typ, names), nil
+	case "managementgroupresourceid":
+		prefix, typ, names, err := p.splitResourceFunctionArgs(fn, args, 1)
+		if err != nil {
+			return "", err
+		}
+		var mg string
+		if len(prefix) == 1 {
+			mg = prefix[0]
+		} else {
+			scope, ok := p.scope.(*ManagementGroup)
+			if !ok {
+				return "", fmt.Errorf("the management group of %s() can't be evaluated against the deployment scope %s", fn.value, scopeDescription(p.scope))
+			}
+			mg = scope.Name
+		}
+		return buildTemplateResourceId((&ManagementGroup{Name: mg}).String(), typ, names), nil
+	case "tenantresourceid":
+		_, typ, names, err := p.splitResourceFunctionArgs(fn, args, 0)
+		if err != nil {
+			return "", err
+		}
+		return buildTemplateResourceId("", typ, names), nil
+	case "extensionresourceid":
+		if len(args) == 0 {
+			return "", fmt.Errorf("%s() requires the resource id argument", fn.value)
+		}
+		_, typ, names, err := p.splitResourceFunctionArgs(fn, args[1:], 0)
+		if err != nil {
+			return "", err
+		}
+		scopeId, err := ParseResourceId(args[0])
+		if err != nil {
+			return "", fmt.Errorf("parsing the resource id argument of %s(): %v", fn.value, err)
+		}
+		prefix := scopeId.String()
+		if prefix == "/" {
+			prefix = ""
+		}
+		return buildTemplateResourceId(prefix, typ, names), nil
+	default:
+		return "", fmt.Errorf("unsupported function %q at offset %d", fn.value, fn.offset)
+	}
+}
+
+// splitResourceFunctionArgs splits the arguments of a resource id function into the leading scope arguments (at most maxPrefix),
+// the resource type and the resource names. The resource type is the first argument that contains a "/".
+func (p *templateExpressionParser) splitResourceFunctionArgs(fn token, args []string, maxPrefix int) (prefix []string, typ string, names []string, err error) {
+	idx := -1
+	for i, arg := range args {
+		if strings.Contains(arg, "/") {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		return nil, "", nil, fmt.Errorf("missing resource type argument of %s()", fn.value)
+	}
+	if idx > maxPrefix {
+		return nil, "", nil, fmt.Errorf("too many arguments before the resource type argument of %s()", fn.value)
+	}
+	prefix, typ, names = args[:idx], args[idx], args[idx+1:]
+	if n := len(strings.Split(typ, "/")) - 1; n != len(names) {
+		return nil, "", nil, fmt.Errorf("resource type %q of %s() expects %d names, got %d", typ, fn.value, n, len(names))
+	}
+	return prefix, typ, names, nil
+}
+
+func buildTemplateResourceId(scope, typ string, names []string) string {
+	segs := strings.Split(typ, "/")
+	out := scope + "/providers/" + segs[0]
+	for i, t := range segs[1:] {
+		out += "/" + t + "/" + names[i]
+	}
+	return out
+}
+
+func scopeDescription(scope RootScope) string {
+	if scope == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("%q", scope.String())
+}
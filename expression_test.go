@@ -0,0 +1,170 @@
+package armid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateExpression(t *testing.T) {
+	rg := &ResourceGroup{SubscriptionId: "sub1", Name: "rg1"}
+	vnet := &ScopedResourceId{
+		AttrParentScope: rg,
+		AttrProvider:    "Microsoft.Network",
+		AttrTypes:       []string{"virtualNetworks"},
+		AttrNames:       []string{"vnet1"},
+	}
+	cases := []struct {
+		name     string
+		id       ResourceId
+		scope    RootScope
+		absolute string
+		relative string
+	}{
+		{
+			name:     "Resource group",
+			id:       rg,
+			scope:    rg,
+			absolute: "subscriptionResourceId('sub1', 'Microsoft.Resources/resourceGroups', 'rg1')",
+			relative: "resourceGroup().id",
+		},
+		{
+			name:     "Management group",
+			id:       &ManagementGroup{Name: "mg1"},
+			scope:    &ManagementGroup{Name: "mg1"},
+			absolute: "tenantResourceId('Microsoft.Management/managementGroups', 'mg1')",
+			relative: "managementGroup().id",
+		},
+		{
+			name: "Resource under resource group",
+			id: &ScopedResourceId{
+				AttrParentScope: rg,
+				AttrProvider:    "Microsoft.Network",
+				AttrTypes:       []string{"virtualNetworks", "subnets"},
+				AttrNames:       []string{"vnet1", "it's"},
+			},
+			scope:    rg,
+			absolute: "resourceId('sub1', 'rg1', 'Microsoft.Network/virtualNetworks/subnets', 'vnet1', 'it''s')",
+			relative: "resourceId('Microsoft.Network/virtualNetworks/subnets', 'vnet1', 'it''s')",
+		},
+		{
+			name: "Resource under subscription",
+			id: &ScopedResourceId{
+				AttrParentScope: &SubscriptionId{Id: "sub1"},
+				AttrProvider:    "Microsoft.Authorization",
+				AttrTypes:       []string{"policyDefinitions"},
+				AttrNames:       []string{"def1"},
+			},
+			scope:    &SubscriptionId{Id: "sub1"},
+			absolute: "subscriptionResourceId('sub1', 'Microsoft.Authorization/policyDefinitions', 'def1')",
+			relative: "subscriptionResourceId('Microsoft.Authorization/policyDefinitions', 'def1')",
+		},
+		{
+			name: "Resource under management group",
+			id: &ScopedResourceId{
+				AttrParentScope: &ManagementGroup{Name: "mg1"},
+				AttrProvider:    "Microsoft.Authorization",
+				AttrTypes:       []string{"policyDefinitions"},
+				AttrNames:       []string{"def1"},
+			},
+			scope:    &ManagementGroup{Name: "mg1"},
+			absolute: "managementGroupResourceId('mg1', 'Microsoft.Authorization/policyDefinitions', 'def1')",
+			relative: "managementGroupResourceId('Microsoft.Authorization/policyDefinitions', 'def1')",
+		},
+		{
+			name: "Resource under tenant",
+			id: &ScopedResourceId{
+				AttrParentScope: &TenantId{},
+				AttrProvider:    "Microsoft.Billing",
+				AttrTypes:       []string{"billingAccounts"},
+				AttrNames:       []string{"ba1"},
+			},
+			absolute: "tenantResourceId('Microsoft.Billing/billingAccounts', 'ba1')",
+			relative: "tenantResourceId('Microsoft.Billing/billingAccounts', 'ba1')",
+		},
+		{
+			name: "Extension resource",
+			id: &ScopedResourceId{
+				AttrParentScope: vnet,
+				AttrProvider:    "Microsoft.Authorization",
+				AttrTypes:       []string{"locks"},
+				AttrNames:       []string{"lock1"},
+			},
+			scope:    rg,
+			absolute: "extensionResourceId(resourceId('sub1', 'rg1', 'Microsoft.Network/virtualNetworks', 'vnet1'), 'Microsoft.Authorization/locks', 'lock1')",
+			relative: "extensionResourceId(resourceId('Microsoft.Network/virtualNetworks', 'vnet1'), 'Microsoft.Authorization/locks', 'lock1')",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := FormatTemplateExpression(tt.id, false)
+			require.NoError(t, err)
+			require.Equal(t, tt.absolute, expr)
+			id, err := ParseTemplateExpression("["+expr+"]", nil)
+			require.NoError(t, err)
+			require.True(t, tt.id.Equal(id), id.String())
+
+			expr, err = FormatTemplateExpression(tt.id, true)
+			require.NoError(t, err)
+			require.Equal(t, tt.relative, expr)
+			id, err = ParseTemplateExpression(expr, tt.scope)
+			require.NoError(t, err)
+			require.True(t, tt.id.Equal(id), id.String())
+		})
+	}
+}
+
+func TestFormatTemplateExpression_error(t *testing.T) {
+	_, err := FormatTemplateExpression(&TenantId{}, false)
+	require.EqualError(t, err, "tenant scope can't be expressed as a template expression")
+	_, err = FormatTemplateExpression(&SubscriptionId{Id: "sub1", AttrTypes: []string{"tagNames"}, AttrNames: []string{"tag1"}}, false)
+	require.EqualError(t, err, `root scope level resource "/subscriptions/sub1/tagNames/tag1" can't be expressed as a template expression`)
+}
+
+func TestParseTemplateExpression_error(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		scope RootScope
+		err   string
+	}{
+		{
+			name:  "Relative without scope",
+			input: "resourceId('Microsoft.Foo/foos', 'foo1')",
+			err:   "the resource group of resourceId() can't be evaluated against the deployment scope <nil>",
+		},
+		{
+			name:  "Mismatched names",
+			input: "resourceId('sub1', 'rg1', 'Microsoft.Foo/foos/bars', 'foo1')",
+			err:   `resource type "Microsoft.Foo/foos/bars" of resourceId() expects 2 names, got 1`,
+		},
+		{
+			name:  "Unsupported function",
+			input: "concat('a', 'b')",
+			err:   `unsupported function "concat" at offset 0`,
+		},
+		{
+			name:  "Unterminated string",
+			input: "tenantResourceId('Microsoft.Foo/foos', 'foo1)",
+			err:   "unterminated string starting at offset 39",
+		},
+		{
+			name:  "Trailing tokens",
+			input: "tenantResourceId('Microsoft.Foo/foos', 'foo1') 'x'",
+			err:   "unexpected string 'x' at offset 47",
+		},
+		{
+			name:  "Unsupported property",
+			input: "resourceGroup().name",
+			scope: &ResourceGroup{SubscriptionId: "sub1", Name: "rg1"},
+			err:   `property "name" of resourceGroup() is not supported`,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTemplateExpression(tt.input, tt.scope)
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
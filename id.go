@@ -0,0 +1,130 @@
+package armid
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ID is a concrete wrapper of ResourceId, which can be used as a field of structs that are (un)marshaled as text, JSON or YAML.
+// It is (un)marshaled as the resource id string literal, while the zero value is (un)marshaled as null (JSON/YAML) or an empty string (text).
+// The original string literal is kept, so that the casing (e.g. of the "providers" segments) is preserved on round trip.
+type ID struct {
+	id  ResourceId
+	raw string
+}
+
+var (
+	_ encoding.TextMarshaler   = ID{}
+	_ encoding.TextUnmarshaler = &ID{}
+	_ json.Marshaler           = ID{}
+	_ json.Unmarshaler         = &ID{}
+	_ yaml.Marshaler           = ID{}
+	_ yaml.Unmarshaler         = &ID{}
+)
+
+// NewID creates an ID from the resource id, which is cloned. A nil resource id results into the zero value.
+func NewID(id ResourceId) ID {
+	if id == nil {
+		return ID{}
+	}
+	return ID{id: id.Clone()}
+}
+
+// ParseID parses the resource id string literal into an ID. An empty string results into the zero value.
+func ParseID(input string) (ID, error) {
+	if input == "" {
+		return ID{}, nil
+	}
+	id, err := ParseResourceId(input)
+	if err != nil {
+		return ID{}, err
+	}
+	return ID{id: id, raw: input}, nil
+}
+
+// ResourceId returns a clone of the underlying resource id, or nil for the zero value.
+func (i ID) ResourceId() ResourceId {
+	if i.id == nil {
+		return nil
+	}
+	return i.id.Clone()
+}
+
+// IsZero tells whether the ID is the zero value.
+func (i ID) IsZero() bool {
+	return i.id == nil
+}
+
+// String returns the original string literal if the ID is parsed, otherwise the string literal of the underlying resource id.
+// It returns an empty string for the zero value.
+func (i ID) String() string {
+	if i.id == nil {
+		return ""
+	}
+	if i.raw != "" {
+		return i.raw
+	}
+	return i.id.String()
+}
+
+// Equal checks the equality of two IDs, which is the same as ResourceId.Equal. Two zero values are regarded as equal.
+func (i ID) Equal(o ID) bool {
+	if i.id == nil || o.id == nil {
+		return i.id == nil && o.id == nil
+	}
+	return i.id.Equal(o.id)
+}
+
+func (i ID) MarshalText() ([]byte, error) {
+	return []byte(i.String()), nil
+}
+
+func (i *ID) UnmarshalText(b []byte) error {
+	id, err := ParseID(string(b))
+	if err != nil {
+		return err
+	}
+	*i = id
+	return nil
+}
+
+func (i ID) MarshalJSON() ([]byte, error) {
+	if i.id == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(i.String())
+}
+
+func (i *ID) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*i = ID{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("unmarshaling resource id: %v", err)
+	}
+	return i.UnmarshalText([]byte(s))
+}
+
+func (i ID) MarshalYAML() (interface{}, error) {
+	if i.id == nil {
+		return nil, nil
+	}
+	return i.String(), nil
+}
+
+func (i *ID) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode && value.Tag == "!!null" {
+		*i = ID{}
+		return nil
+	}
+	var s string
+	if err := value.Decode(&s); err != nil {
+		return fmt.Errorf("unmarshaling resource id: %v", err)
+	}
+	return i.UnmarshalText([]byte(s))
+}
@@ -0,0 +1,83 @@
+package armid
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+type idHolder struct {
+	Id       ID  `json:"id" yaml:"id"`
+	Optional ID  `json:"optional" yaml:"optional"`
+	Ptr      *ID `json:"ptr,omitempty" yaml:"ptr,omitempty"`
+}
+
+func TestID_JSON(t *testing.T) {
+	input := `{"id":"/SUBSCRIPTIONS/sub1/resourceGroups/rg1/PROVIDERS/Microsoft.Foo/foos/foo1","optional":null}`
+	var h idHolder
+	require.NoError(t, json.Unmarshal([]byte(input), &h))
+	require.False(t, h.Id.IsZero())
+	require.True(t, h.Optional.IsZero())
+	require.Nil(t, h.Ptr)
+	require.Equal(t, "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Foo/foos/foo1", h.Id.ResourceId().String())
+
+	b, err := json.Marshal(h)
+	require.NoError(t, err)
+	require.JSONEq(t, input, string(b))
+
+	require.NoError(t, json.Unmarshal([]byte(`{"id":""}`), &h))
+	require.True(t, h.Id.IsZero())
+	require.Nil(t, h.Id.ResourceId())
+
+	require.EqualError(t, json.Unmarshal([]byte(`{"id":"subscriptions"}`), &h), `id should start with "/"`)
+	require.EqualError(t, json.Unmarshal([]byte(`{"id":1}`), &h), `unmarshaling resource id: json: cannot unmarshal number into Go value of type string`)
+}
+
+func TestID_YAML(t *testing.T) {
+	input := `id: /SUBSCRIPTIONS/sub1/resourceGroups/rg1
+optional: null
+ptr: /providers/Microsoft.Management/managementGroups/mg1
+`
+	var h idHolder
+	require.NoError(t, yaml.Unmarshal([]byte(input), &h))
+	require.Equal(t, "/SUBSCRIPTIONS/sub1/resourceGroups/rg1", h.Id.String())
+	require.True(t, h.Optional.IsZero())
+	require.NotNil(t, h.Ptr)
+	require.IsType(t, &ManagementGroup{}, h.Ptr.ResourceId())
+
+	b, err := yaml.Marshal(h)
+	require.NoError(t, err)
+	require.Equal(t, input, string(b))
+
+	require.Error(t, yaml.Unmarshal([]byte("id: [a]"), &h))
+}
+
+func TestID_Text(t *testing.T) {
+	id, err := ParseID("/subscriptions/sub1")
+	require.NoError(t, err)
+	b, err := id.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "/subscriptions/sub1", string(b))
+
+	var zero ID
+	b, err = zero.MarshalText()
+	require.NoError(t, err)
+	require.Empty(t, b)
+
+	rg, err := ParseResourceId("/subscriptions/sub1/resourceGroups/rg1")
+	require.NoError(t, err)
+	nid := NewID(rg)
+	require.Equal(t, "/subscriptions/sub1/resourceGroups/rg1", nid.String())
+	other, err := ParseID("/SUBSCRIPTIONS/SUB1/RESOURCEGROUPS/RG1")
+	require.NoError(t, err)
+	require.True(t, nid.Equal(other))
+	require.False(t, nid.Equal(zero))
+	require.True(t, zero.Equal(ID{}))
+
+	m := map[ID]int{nid: 1}
+	b, err = json.Marshal(m)
+	require.NoError(t, err)
+	require.Equal(t, `{"/subscriptions/sub1/resourceGroups/rg1":1}`, string(b))
+}
@@ -0,0 +1,143 @@
+package armid
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kind classifies a resource id by its shape.
+type Kind int
+
+const (
+	// KindTenant is the tenant root scope, i.e. "/".
+	KindTenant Kind = iota + 1
+	// KindManagementGroup is the management group root scope, e.g. "/providers/Microsoft.Management/managementGroups/mg1".
+	KindManagementGroup
+	// KindSubscription is the subscription root scope, e.g. "/subscriptions/0000".
+	KindSubscription
+	// KindResourceGroup is the resource group root scope, e.g. "/subscriptions/0000/resourceGroups/rg1".
+	KindResourceGroup
+	// KindRootChild is a resource defined directly under a root scope, e.g. "/subscriptions/0000/tagNames/tag1".
+	KindRootChild
+	// KindProvider is a provider level resource id, e.g. "/subscriptions/0000/providers/Microsoft.Foo".
+	KindProvider
+	// KindTopLevel is a top level resource scoped to a root scope, e.g. "/subscriptions/0000/resourceGroups/rg1/providers/Microsoft.Foo/foos/foo1".
+	KindTopLevel
+	// KindChild is a child resource scoped to a root scope, e.g. "/subscriptions/0000/resourceGroups/rg1/providers/Microsoft.Foo/foos/foo1/bars/bar1".
+	KindChild
+	// KindExtension is a resource scoped to another resource, e.g. "/subscriptions/0000/resourceGroups/rg1/providers/Microsoft.Foo/foos/foo1/providers/Microsoft.Authorization/locks/lock1".
+	KindExtension
+	// KindLocation is a resource (or the location itself) under a location of a provider, e.g. "/subscriptions/0000/providers/Microsoft.Foo/locations/westus/operationStatuses/op1".
+	KindLocation
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindTenant:
+		return "Tenant"
+	case KindManagementGroup:
+		return "ManagementGroup"
+	case KindSubscription:
+		return "Subscription"
+	case KindResourceGroup:
+		return "ResourceGroup"
+	case KindRootChild:
+		return "RootChild"
+	case KindProvider:
+		return "Provider"
+	case KindTopLevel:
+		return "TopLevel"
+	case KindChild:
+		return "Child"
+	case KindExtension:
+		return "Extension"
+	case KindLocation:
+		return "Location"
+	default:
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+}
+
+// IsRootScope tells whether the kind is one of the root scopes.
+func (k Kind) IsRootScope() bool {
+	switch k {
+	case KindTenant, KindManagementGroup, KindSubscription, KindResourceGroup:
+		return true
+	}
+	return false
+}
+
+// KindOf returns the kind of the resource id. The kinds of a scoped resource id are checked in the following order:
+// KindProvider, KindLocation, KindExtension, KindTopLevel and KindChild.
+func KindOf(id ResourceId) Kind {
+	switch id := id.(type) {
+	case *TenantId:
+		return KindTenant
+	case *SubscriptionId:
+		if len(id.AttrTypes) != 0 {
+			return KindRootChild
+		}
+		return KindSubscription
+	case *ResourceGroup:
+		if len(id.AttrTypes) != 0 {
+			return KindRootChild
+		}
+		return KindResourceGroup
+	case *ManagementGroup:
+		if len(id.AttrTypes) != 0 {
+			return KindRootChild
+		}
+		return KindManagementGroup
+	case *ScopedResourceId:
+		switch {
+		case len(id.AttrTypes) == 0:
+			return KindProvider
+		case strings.EqualFold(id.AttrTypes[0], "locations"):
+			return KindLocation
+		case IsExtension(id):
+			return KindExtension
+		case len(id.AttrTypes) == 1:
+			return KindTopLevel
+		default:
+			return KindChild
+		}
+	}
+	return 0
+}
+
+// IsExtension tells whether the resource id is scoped to another resource (rather than a root scope), regardless of its other traits.
+func IsExtension(id ResourceId) bool {
+	pid := id.ParentScope()
+	return pid != nil && !isBareRootScope(pid)
+}
+
+// IsChild tells whether the resource id is a child resource (or a root scope level resource) that has a parent resource within the
+// same provider (or root scope), regardless of its other traits.
+func IsChild(id ResourceId) bool {
+	switch id := id.(type) {
+	case *ScopedResourceId:
+		return len(id.AttrTypes) > 1
+	case *SubscriptionId:
+		return len(id.AttrTypes) > 1
+	case *ResourceGroup:
+		return len(id.AttrTypes) > 1
+	case *ManagementGroup:
+		return len(id.AttrTypes) > 1
+	}
+	return false
+}
+
+// IsTopLevel tells whether the resource id is a top level resource of a provider (or root scope), regardless of its other traits.
+func IsTopLevel(id ResourceId) bool {
+	switch id := id.(type) {
+	case *ScopedResourceId:
+		return len(id.AttrTypes) == 1
+	case *SubscriptionId:
+		return len(id.AttrTypes) == 1
+	case *ResourceGroup:
+		return len(id.AttrTypes) == 1
+	case *ManagementGroup:
+		return len(id.AttrTypes) == 1
+	}
+	return false
+}
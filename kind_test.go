@@ -0,0 +1,97 @@
+package armid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceId_Kind(t *testing.T) {
+	cases := []struct {
+		input     string
+		expect    Kind
+		extension bool
+		child     bool
+		topLevel  bool
+	}{
+		{
+			input:  "/",
+			expect: KindTenant,
+		},
+		{
+			input:  "/providers/Microsoft.Management/managementGroups/mg1",
+			expect: KindManagementGroup,
+		},
+		{
+			input:  "/subscriptions/sub1",
+			expect: KindSubscription,
+		},
+		{
+			input:  "/subscriptions/sub1/resourceGroups/rg1",
+			expect: KindResourceGroup,
+		},
+		{
+			input:    "/subscriptions/sub1/tagNames/tag1",
+			expect:   KindRootChild,
+			topLevel: true,
+		},
+		{
+			input:  "/subscriptions/sub1/tagNames/tag1/tagValues/value1",
+			expect: KindRootChild,
+			child:  true,
+		},
+		{
+			input:  "/subscriptions/sub1/providers/Microsoft.Foo",
+			expect: KindProvider,
+		},
+		{
+			input:    "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Foo/foos/foo1",
+			expect:   KindTopLevel,
+			topLevel: true,
+		},
+		{
+			input:  "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Foo/foos/foo1/bars/bar1",
+			expect: KindChild,
+			child:  true,
+		},
+		{
+			input:     "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Foo/foos/foo1/providers/Microsoft.Authorization/locks/lock1",
+			expect:    KindExtension,
+			extension: true,
+			topLevel:  true,
+		},
+		{
+			input:     "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Foo/foos/foo1/providers/Microsoft.Bar/bars/bar1/bazs/baz1",
+			expect:    KindExtension,
+			extension: true,
+			child:     true,
+		},
+		{
+			input:  "/subscriptions/sub1/providers/Microsoft.Foo/locations/westus/operationStatuses/op1",
+			expect: KindLocation,
+			child:  true,
+		},
+		{
+			input:    "/subscriptions/sub1/providers/Microsoft.Foo/Locations/westus",
+			expect:   KindLocation,
+			topLevel: true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.input, func(t *testing.T) {
+			id, err := ParseResourceId(tt.input)
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, KindOf(id), KindOf(id).String())
+			require.Equal(t, tt.extension, IsExtension(id))
+			require.Equal(t, tt.child, IsChild(id))
+			require.Equal(t, tt.topLevel, IsTopLevel(id))
+		})
+	}
+}
+
+func TestKind_String(t *testing.T) {
+	require.Equal(t, "ResourceGroup", KindResourceGroup.String())
+	require.Equal(t, "Kind(0)", Kind(0).String())
+	require.True(t, KindManagementGroup.IsRootScope())
+	require.False(t, KindRootChild.IsRootScope())
+}
@@ -0,0 +1,119 @@
+package armid
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ActionId represents a POST action on a resource, which is a resource id followed by a trailing action name, e.g.
+// "/subscriptions/0000/resourceGroups/rg1/providers/Microsoft.Storage/storageAccounts/sa1/listKeys".
+//
+// Note that an action id is indistinguishable from a CollectionId by syntax, it is up to the caller to decide which one to parse as.
+type ActionId struct {
+	// Resource is the resource id that the action is performed on.
+	Resource ResourceId
+
+	// Action is the name of the action, e.g. "listKeys".
+	Action string
+}
+
+// ParseActionId parses the action id string literal.
+func ParseActionId(input string) (*ActionId, error) {
+	id, action, err := splitTrailingSegment(input, "has no trailing segment after the resource id")
+	if err != nil {
+		return nil, err
+	}
+	return &ActionId{Resource: id, Action: action}, nil
+}
+
+// String returns the action id literal.
+func (a *ActionId) String() string {
+	var prefix string
+	if _, ok := a.Resource.(*TenantId); !ok {
+		prefix = a.Resource.String()
+	}
+	return prefix + "/" + a.Action
+}
+
+// operationTypes are the well known resource types of the location scoped asynchronous operations.
+var operationTypes = []string{
+	"operationStatuses",
+	"operationResults",
+	"asyncOperations",
+	"operations",
+}
+
+func isOperationType(typ string) bool {
+	for _, t := range operationTypes {
+		if strings.EqualFold(t, typ) {
+			return true
+		}
+	}
+	return false
+}
+
+// OperationId represents a location scoped asynchronous operation status (or result) id, e.g.
+// "/subscriptions/0000/providers/Microsoft.Foo/locations/eastus/operationStatuses/op1".
+type OperationId struct {
+	// Scope is the scope of the owning provider, e.g. "/subscriptions/0000".
+	Scope ResourceId
+
+	// Provider is the provider namespace that owns the operation, e.g. "Microsoft.Foo".
+	Provider string
+
+	// Location is the location of the operation, e.g. "eastus".
+	Location string
+
+	// Type is the operation resource type, which is one of "operationStatuses", "operationResults", "asyncOperations" and "operations".
+	Type string
+
+	// Name is the operation id.
+	Name string
+
+	locationsLiteral string
+}
+
+// ParseOperationId parses the operation id string literal.
+func ParseOperationId(input string) (*OperationId, error) {
+	id, err := ParseResourceId(input)
+	if err != nil {
+		return nil, err
+	}
+	return OperationIdOf(id)
+}
+
+// OperationIdOf converts the resource id to an OperationId. The resource id must be a scoped resource id, whose types are "locations" and
+// one of the operation types, e.g. "/subscriptions/0000/providers/Microsoft.Foo/locations/eastus/operationStatuses/op1".
+func OperationIdOf(id ResourceId) (*OperationId, error) {
+	sid, ok := id.(*ScopedResourceId)
+	if !ok || len(sid.AttrTypes) != 2 || !strings.EqualFold(sid.AttrTypes[0], "locations") || !isOperationType(sid.AttrTypes[1]) {
+		return nil, fmt.Errorf("%q is not an operation id, expect the form of .../providers/<namespace>/locations/<location>/<%s>/<id>", id.String(), strings.Join(operationTypes, "|"))
+	}
+	return &OperationId{
+		Scope:            sid.AttrParentScope.Clone(),
+		Provider:         sid.AttrProvider,
+		Location:         sid.AttrNames[0],
+		Type:             sid.AttrTypes[1],
+		Name:             sid.AttrNames[1],
+		locationsLiteral: sid.AttrTypes[0],
+	}, nil
+}
+
+// ResourceId returns the resource id of the operation.
+func (o *OperationId) ResourceId() ResourceId {
+	locations := o.locationsLiteral
+	if locations == "" {
+		locations = "locations"
+	}
+	return &ScopedResourceId{
+		AttrParentScope: o.Scope.Clone(),
+		AttrProvider:    o.Provider,
+		AttrTypes:       []string{locations, o.Type},
+		AttrNames:       []string{o.Location, o.Name},
+	}
+}
+
+// String returns the operation id literal.
+func (o *OperationId) String() string {
+	return o.ResourceId().String()
+}
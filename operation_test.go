@@ -0,0 +1,125 @@
+package armid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseActionId(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		resource string
+		action   string
+		err      string
+	}{
+		{
+			name:     "Resource action",
+			input:    "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Storage/storageAccounts/sa1/listKeys",
+			resource: "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Storage/storageAccounts/sa1",
+			action:   "listKeys",
+		},
+		{
+			name:     "Provider action",
+			input:    "/subscriptions/sub1/providers/Microsoft.Foo/register",
+			resource: "/subscriptions/sub1/providers/Microsoft.Foo",
+			action:   "register",
+		},
+		{
+			name:     "Root scope action",
+			input:    "/subscriptions/sub1/resourceGroups/rg1/exportTemplate",
+			resource: "/subscriptions/sub1/resourceGroups/rg1",
+			action:   "exportTemplate",
+		},
+		{
+			name:  "Missing action",
+			input: "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Compute/virtualMachines/vm1",
+			err:   `"/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Compute/virtualMachines/vm1" has no trailing segment after the resource id`,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := ParseActionId(tt.input)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.resource, a.Resource.String())
+			require.Equal(t, tt.action, a.Action)
+			require.Equal(t, tt.input, a.String())
+		})
+	}
+}
+
+func TestParseOperationId(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		scope    string
+		provider string
+		location string
+		typ      string
+		opName   string
+		err      string
+	}{
+		{
+			name:     "Operation status",
+			input:    "/subscriptions/sub1/providers/Microsoft.Foo/locations/eastus/operationStatuses/op1",
+			scope:    "/subscriptions/sub1",
+			provider: "Microsoft.Foo",
+			location: "eastus",
+			typ:      "operationStatuses",
+			opName:   "op1",
+		},
+		{
+			name:     "Operation result under resource group",
+			input:    "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Foo/Locations/westus/OperationResults/op1",
+			scope:    "/subscriptions/sub1/resourceGroups/rg1",
+			provider: "Microsoft.Foo",
+			location: "westus",
+			typ:      "OperationResults",
+			opName:   "op1",
+		},
+		{
+			name:     "Async operation",
+			input:    "/providers/Microsoft.Foo/locations/eastus/asyncOperations/op1",
+			scope:    "/",
+			provider: "Microsoft.Foo",
+			location: "eastus",
+			typ:      "asyncOperations",
+			opName:   "op1",
+		},
+		{
+			name:  "Not an operation",
+			input: "/subscriptions/sub1/providers/Microsoft.Foo/locations/eastus/foos/foo1",
+			err:   `"/subscriptions/sub1/providers/Microsoft.Foo/locations/eastus/foos/foo1" is not an operation id, expect the form of .../providers/<namespace>/locations/<location>/<operationStatuses|operationResults|asyncOperations|operations>/<id>`,
+		},
+		{
+			name:  "Invalid id",
+			input: "/subscriptions/sub1/providers/Microsoft.Foo/locations/eastus/operationStatuses",
+			err:   `extending for RP Microsoft.Foo: missing resource type name after type operationStatuses`,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			op, err := ParseOperationId(tt.input)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.scope, op.Scope.String())
+			require.Equal(t, tt.provider, op.Provider)
+			require.Equal(t, tt.location, op.Location)
+			require.Equal(t, tt.typ, op.Type)
+			require.Equal(t, tt.opName, op.Name)
+			require.Equal(t, tt.input, op.String())
+		})
+	}
+
+	op := &OperationId{Scope: &SubscriptionId{Id: "sub1"}, Provider: "Microsoft.Foo", Location: "eastus", Type: "operations", Name: "op1"}
+	require.Equal(t, "/subscriptions/sub1/providers/Microsoft.Foo/locations/eastus/operations/op1", op.String())
+	require.Equal(t, KindLocation, KindOf(op.ResourceId()))
+}
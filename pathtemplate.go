@@ -0,0 +1,251 @@
+package armid
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Template is a compiled Swagger-style path template, e.g.
+// "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Network/virtualNetworks/{virtualNetworkName}".
+//
+// Each segment of the template is either a literal, or a placeholder that occupies the whole segment.
+// A placeholder as the first segment (e.g. "/{scope}/providers/Microsoft.Authorization/locks/{lockName}") is a scope placeholder,
+// which stands for an arbitrary (nested) resource id, rather than a single segment.
+type Template struct {
+	raw  string
+	segs []templateSegment
+}
+
+type templateSegment struct {
+	// literal is set for the literal segment
+	literal string
+	// param is set for the placeholder segment
+	param string
+}
+
+func (seg templateSegment) String() string {
+	if seg.param != "" {
+		return "{" + seg.param + "}"
+	}
+	return seg.literal
+}
+
+// CompileTemplate compiles a Swagger-style path template.
+func CompileTemplate(template string) (*Template, error) {
+	if !strings.HasPrefix(template, "/") {
+		return nil, fmt.Errorf(`template should start with "/"`)
+	}
+	path := template
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	t := &Template{raw: template}
+	if path == "/" {
+		return t, nil
+	}
+	params := map[string]bool{}
+	for i, seg := range strings.Split(path[1:], "/") {
+		if seg == "" {
+			return nil, fmt.Errorf(`empty segment found behind %dth "/"`, i+1)
+		}
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			name := seg[1 : len(seg)-1]
+			if name == "" || strings.ContainsAny(name, "{}") {
+				return nil, fmt.Errorf("invalid placeholder %q", seg)
+			}
+			if params[name] {
+				return nil, fmt.Errorf("duplicate placeholder %q", seg)
+			}
+			params[name] = true
+			t.segs = append(t.segs, templateSegment{param: name})
+			continue
+		}
+		if strings.ContainsAny(seg, "{}") {
+			return nil, fmt.Errorf("placeholder %q doesn't occupy the whole segment", seg)
+		}
+		t.segs = append(t.segs, templateSegment{literal: seg})
+	}
+	return t, nil
+}
+
+// MustCompileTemplate is like CompileTemplate but panics if the template can't be compiled.
+func MustCompileTemplate(template string) *Template {
+	t, err := CompileTemplate(template)
+	if err != nil {
+		panic(fmt.Sprintf("compiling template %q: %v", template, err))
+	}
+	return t
+}
+
+// String returns the template literal.
+func (t *Template) String() string {
+	return t.raw
+}
+
+// Params returns the placeholder names in order, including the scope placeholder.
+func (t *Template) Params() []string {
+	var out []string
+	for _, seg := range t.segs {
+		if seg.param != "" {
+			out = append(out, seg.param)
+		}
+	}
+	return out
+}
+
+// ScopeParam returns the name of the scope placeholder, or empty if there is none.
+func (t *Template) ScopeParam() string {
+	if len(t.segs) != 0 && t.segs[0].param != "" {
+		return t.segs[0].param
+	}
+	return ""
+}
+
+// TemplateMismatchError is the error returned by Template.Match when the resource id doesn't match the template.
+type TemplateMismatchError struct {
+	// Template is the template literal.
+	Template string
+
+	// Id is the resource id literal.
+	Id string
+
+	// SegmentIndex is the index of the mismatched template segment, counting from the first segment following the leading "/".
+	// It equals to the number of the template segments if the id has more segments than the template.
+	SegmentIndex int
+
+	// Expected is the expected template segment, which is empty if the id has more segments than the template.
+	Expected string
+
+	// Actual is the actual segment of the id, which is empty if the id has less segments than the template.
+	Actual string
+}
+
+func (e *TemplateMismatchError) Error() string {
+	switch {
+	case e.Actual == "":
+		return fmt.Sprintf("id %q doesn't match template %q: missing segment %q", e.Id, e.Template, e.Expected)
+	case e.Expected == "":
+		return fmt.Sprintf("id %q doesn't match template %q: unexpected segment %q", e.Id, e.Template, e.Actual)
+	default:
+		return fmt.Sprintf("id %q doesn't match template %q: segment %d expects %q, got %q", e.Id, e.Template, e.SegmentIndex, e.Expected, e.Actual)
+	}
+}
+
+// Match matches the resource id against the template. The literal segments are matched case-insensitively.
+// On success, it returns the values of the placeholders. The value of the scope placeholder is the scope id without the leading "/",
+// following the Swagger convention, e.g. "subscriptions/0000/resourceGroups/rg1".
+// Otherwise, a *TemplateMismatchError is returned.
+func (t *Template) Match(id ResourceId) (map[string]string, error) {
+	idStr := id.String()
+	var idSegs []string
+	if idStr != "/" {
+		idSegs = strings.Split(idStr[1:], "/")
+	}
+	mismatch := func(idx int) error {
+		err := &TemplateMismatchError{
+			Template:     t.raw,
+			Id:           idStr,
+			SegmentIndex: idx,
+		}
+		if idx < len(t.segs) {
+			err.Expected = t.segs[idx].String()
+		}
+		return err
+	}
+
+	params := map[string]string{}
+	tsegs := t.segs
+	offset := 0
+	if name := t.ScopeParam(); name != "" {
+		tsegs = t.segs[1:]
+		offset = len(idSegs) - len(tsegs)
+		if offset < 1 {
+			return nil, mismatch(0)
+		}
+		scope := strings.Join(idSegs[:offset], "/")
+		if _, err := ParseResourceId("/" + scope); err != nil {
+			e := mismatch(0).(*TemplateMismatchError)
+			e.Actual = scope
+			return nil, e
+		}
+		params[name] = scope
+		// Align the remaining id segments with the template segments
+		idSegs = idSegs[offset-1:]
+	}
+	start := len(t.segs) - len(tsegs)
+
+	for i := start; i < len(t.segs); i++ {
+		if i >= len(idSegs) {
+			return nil, mismatch(i)
+		}
+		seg := t.segs[i]
+		if seg.param != "" {
+			params[seg.param] = idSegs[i]
+			continue
+		}
+		if !strings.EqualFold(seg.literal, idSegs[i]) {
+			err := mismatch(i).(*TemplateMismatchError)
+			err.Actual = idSegs[i]
+			return nil, err
+		}
+	}
+	if len(idSegs) > len(t.segs) {
+		err := mismatch(len(t.segs)).(*TemplateMismatchError)
+		err.Actual = idSegs[len(t.segs)]
+		return nil, err
+	}
+	return params, nil
+}
+
+// Render renders the template with the given placeholder values into a ResourceId.
+// Every placeholder must be given a value, and no value is allowed for an unknown placeholder.
+// The value of the scope placeholder is a resource id, with or without the leading "/", which can't be the tenant scope.
+// The other values must be a single non-empty segment.
+func (t *Template) Render(params map[string]string) (ResourceId, error) {
+	var missing, extra []string
+	known := map[string]bool{}
+	for _, name := range t.Params() {
+		known[name] = true
+		if _, ok := params[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	for name := range params {
+		if !known[name] {
+			extra = append(extra, name)
+		}
+	}
+	if len(missing) != 0 {
+		return nil, fmt.Errorf("missing value for placeholder(s): %s", strings.Join(missing, ", "))
+	}
+	if len(extra) != 0 {
+		sort.Strings(extra)
+		return nil, fmt.Errorf("unknown placeholder(s): %s", strings.Join(extra, ", "))
+	}
+
+	segs := make([]string, 0, len(t.segs))
+	for i, seg := range t.segs {
+		if seg.param == "" {
+			segs = append(segs, seg.literal)
+			continue
+		}
+		v := params[seg.param]
+		if i == 0 && t.ScopeParam() != "" {
+			scope, err := ParseResourceId("/" + strings.TrimPrefix(v, "/"))
+			if err != nil {
+				return nil, fmt.Errorf("invalid value of scope placeholder %q: %v", seg.param, err)
+			}
+			if _, ok := scope.(*TenantId); ok {
+				return nil, fmt.Errorf("invalid value of scope placeholder %q: tenant scope is not allowed", seg.param)
+			}
+			segs = append(segs, strings.TrimPrefix(scope.String(), "/"))
+			continue
+		}
+		if v == "" || strings.Contains(v, "/") {
+			return nil, fmt.Errorf("invalid value of placeholder %q: %q is not a single segment", seg.param, v)
+		}
+		segs = append(segs, v)
+	}
+	return ParseResourceId("/" + strings.Join(segs, "/"))
+}
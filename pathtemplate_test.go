@@ -0,0 +1,261 @@
+package armid
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileTemplate(t *testing.T) {
+	cases := []struct {
+		name       string
+		input      string
+		params     []string
+		scopeParam string
+		err        string
+	}{
+		{
+			name:   "Resource",
+			input:  "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Network/virtualNetworks/{virtualNetworkName}",
+			params: []string{"subscriptionId", "resourceGroupName", "virtualNetworkName"},
+		},
+		{
+			name:       "Scope placeholder",
+			input:      "/{scope}/providers/Microsoft.Authorization/locks/{lockName}",
+			params:     []string{"scope", "lockName"},
+			scopeParam: "scope",
+		},
+		{
+			name:  "Tenant",
+			input: "/",
+		},
+		{
+			name:  "Partial placeholder",
+			input: "/subscriptions/{subscriptionId}/resourceGroups/rg-{name}",
+			err:   `placeholder "rg-{name}" doesn't occupy the whole segment`,
+		},
+		{
+			name:  "Duplicate placeholder",
+			input: "/subscriptions/{name}/resourceGroups/{name}",
+			err:   `duplicate placeholder "{name}"`,
+		},
+		{
+			name:  "Missing leading slash",
+			input: "subscriptions/{subscriptionId}",
+			err:   `template should start with "/"`,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			tpl, err := CompileTemplate(tt.input)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.input, tpl.String())
+			require.Equal(t, tt.params, tpl.Params())
+			require.Equal(t, tt.scopeParam, tpl.ScopeParam())
+		})
+	}
+}
+
+func TestTemplate_Match(t *testing.T) {
+	cases := []struct {
+		name     string
+		template string
+		id       string
+		expect   map[string]string
+		mismatch *TemplateMismatchError
+	}{
+		{
+			name:     "Match case-insensitively",
+			template: "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Network/virtualNetworks/{virtualNetworkName}",
+			id:       "/SUBSCRIPTIONS/sub1/resourcegroups/rg1/providers/microsoft.network/VIRTUALNETWORKS/Vnet1",
+			expect: map[string]string{
+				"subscriptionId":     "sub1",
+				"resourceGroupName":  "rg1",
+				"virtualNetworkName": "Vnet1",
+			},
+		},
+		{
+			name:     "Match scope placeholder",
+			template: "/{scope}/providers/Microsoft.Authorization/locks/{lockName}",
+			id:       "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/providers/Microsoft.Authorization/locks/lock1",
+			expect: map[string]string{
+				"scope":    "subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1",
+				"lockName": "lock1",
+			},
+		},
+		{
+			name:     "Match tenant",
+			template: "/",
+			id:       "/",
+			expect:   map[string]string{},
+		},
+		{
+			name:     "Mismatched literal",
+			template: "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Network/virtualNetworks/{virtualNetworkName}",
+			id:       "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Compute/virtualMachines/vm1",
+			mismatch: &TemplateMismatchError{
+				SegmentIndex: 5,
+				Expected:     "Microsoft.Network",
+				Actual:       "Microsoft.Compute",
+			},
+		},
+		{
+			name:     "Mismatched literal after scope placeholder",
+			template: "/{scope}/providers/Microsoft.Authorization/locks/{lockName}",
+			id:       "/subscriptions/sub1/providers/Microsoft.Authorization/roleAssignments/ra1",
+			mismatch: &TemplateMismatchError{
+				SegmentIndex: 3,
+				Expected:     "locks",
+				Actual:       "roleAssignments",
+			},
+		},
+		{
+			name:     "Missing scope",
+			template: "/{scope}/providers/Microsoft.Authorization/locks/{lockName}",
+			id:       "/providers/Microsoft.Authorization/locks/lock1",
+			mismatch: &TemplateMismatchError{
+				SegmentIndex: 0,
+				Expected:     "{scope}",
+			},
+		},
+		{
+			name:     "Too short",
+			template: "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}",
+			id:       "/subscriptions/sub1",
+			mismatch: &TemplateMismatchError{
+				SegmentIndex: 2,
+				Expected:     "resourceGroups",
+			},
+		},
+		{
+			name:     "Too long",
+			template: "/subscriptions/{subscriptionId}",
+			id:       "/subscriptions/sub1/resourceGroups/rg1",
+			mismatch: &TemplateMismatchError{
+				SegmentIndex: 2,
+				Actual:       "resourceGroups",
+			},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := ParseResourceId(tt.id)
+			require.NoError(t, err)
+			params, err := MustCompileTemplate(tt.template).Match(id)
+			if tt.mismatch != nil {
+				var merr *TemplateMismatchError
+				require.True(t, errors.As(err, &merr), err)
+				tt.mismatch.Template = tt.template
+				tt.mismatch.Id = tt.id
+				require.Equal(t, tt.mismatch, merr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, params)
+		})
+	}
+}
+
+func TestTemplate_Render(t *testing.T) {
+	cases := []struct {
+		name     string
+		template string
+		params   map[string]string
+		expect   ResourceId
+		err      string
+	}{
+		{
+			name:     "Resource",
+			template: "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Network/virtualNetworks/{virtualNetworkName}",
+			params: map[string]string{
+				"subscriptionId":     "sub1",
+				"resourceGroupName":  "rg1",
+				"virtualNetworkName": "vnet1",
+			},
+			expect: &ScopedResourceId{
+				AttrParentScope: &ResourceGroup{SubscriptionId: "sub1", Name: "rg1"},
+				AttrProvider:    "Microsoft.Network",
+				AttrTypes:       []string{"virtualNetworks"},
+				AttrNames:       []string{"vnet1"},
+			},
+		},
+		{
+			name:     "Scope placeholder",
+			template: "/{resourceUri}/providers/Microsoft.Authorization/locks/{lockName}",
+			params: map[string]string{
+				"resourceUri": "/subscriptions/sub1/providers/Microsoft.Foo/foos/foo1",
+				"lockName":    "lock1",
+			},
+			expect: &ScopedResourceId{
+				AttrParentScope: &ScopedResourceId{
+					AttrParentScope: &SubscriptionId{Id: "sub1"},
+					AttrProvider:    "Microsoft.Foo",
+					AttrTypes:       []string{"foos"},
+					AttrNames:       []string{"foo1"},
+				},
+				AttrProvider: "Microsoft.Authorization",
+				AttrTypes:    []string{"locks"},
+				AttrNames:    []string{"lock1"},
+			},
+		},
+		{
+			name:     "Scope placeholder without leading slash",
+			template: "/{scope}/providers/Microsoft.Authorization/locks/{lockName}",
+			params: map[string]string{
+				"scope":    "subscriptions/sub1",
+				"lockName": "lock1",
+			},
+			expect: &ScopedResourceId{
+				AttrParentScope: &SubscriptionId{Id: "sub1"},
+				AttrProvider:    "Microsoft.Authorization",
+				AttrTypes:       []string{"locks"},
+				AttrNames:       []string{"lock1"},
+			},
+		},
+		{
+			name:     "Missing value",
+			template: "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}",
+			params:   map[string]string{},
+			err:      "missing value for placeholder(s): subscriptionId, resourceGroupName",
+		},
+		{
+			name:     "Extra value",
+			template: "/subscriptions/{subscriptionId}",
+			params:   map[string]string{"subscriptionId": "sub1", "foo": "x", "bar": "y"},
+			err:      "unknown placeholder(s): bar, foo",
+		},
+		{
+			name:     "Multi-segment value",
+			template: "/subscriptions/{subscriptionId}",
+			params:   map[string]string{"subscriptionId": "sub1/resourceGroups/rg1"},
+			err:      `invalid value of placeholder "subscriptionId": "sub1/resourceGroups/rg1" is not a single segment`,
+		},
+		{
+			name:     "Tenant scope value",
+			template: "/{scope}/providers/Microsoft.Authorization/locks/{lockName}",
+			params:   map[string]string{"scope": "/", "lockName": "lock1"},
+			err:      `invalid value of scope placeholder "scope": tenant scope is not allowed`,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			tpl := MustCompileTemplate(tt.template)
+			id, err := tpl.Render(tt.params)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, id)
+
+			params, err := tpl.Match(id)
+			require.NoError(t, err)
+			require.Len(t, params, len(tt.params))
+		})
+	}
+}
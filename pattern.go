@@ -0,0 +1,152 @@
+package armid
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Pattern is a compiled wildcard pattern over resource ids, e.g.
+// "/subscriptions/*/resourceGroups/*/providers/Microsoft.Network/virtualNetworks/*/subnets/*" or "/subscriptions/s1/**".
+//
+// Each segment of the pattern is matched against one segment of the resource id case-insensitively, consistent with ResourceId.Equal.
+// The segment supports the wildcards defined by path.Match, e.g. "*" matches any single segment, "vnet-*" matches any segment prefixed by "vnet-".
+// The "providers" segments that split the scopes can only be matched by a literal "providers".
+//
+// A "**" segment matches zero or more segments, across nested scopes. It can only start and end at the boundaries of
+// the resource ids along the way (i.e. the id itself, its ancestors and the provider level ids), so that a type is never matched as a name and vice versa.
+// E.g. "/subscriptions/s1/**" matches the subscription s1 and everything under it.
+type Pattern struct {
+	raw  string
+	segs []string
+}
+
+// CompilePattern compiles a resource id pattern.
+func CompilePattern(pattern string) (*Pattern, error) {
+	if !strings.HasPrefix(pattern, "/") {
+		return nil, fmt.Errorf(`pattern should start with "/"`)
+	}
+	p := &Pattern{raw: pattern}
+	if pattern == "/" {
+		return p, nil
+	}
+	for i, seg := range strings.Split(pattern[1:], "/") {
+		if seg == "" {
+			return nil, fmt.Errorf(`empty segment found behind %dth "/"`, i+1)
+		}
+		if seg != "**" {
+			if strings.Contains(seg, "**") {
+				return nil, fmt.Errorf(`"**" should occupy the whole segment, got %q`, seg)
+			}
+			if _, err := path.Match(seg, ""); err != nil {
+				return nil, fmt.Errorf("invalid segment %q: %v", seg, err)
+			}
+		}
+		p.segs = append(p.segs, strings.ToLower(seg))
+	}
+	return p, nil
+}
+
+// MustCompilePattern is like CompilePattern but panics if the pattern can't be compiled.
+func MustCompilePattern(pattern string) *Pattern {
+	p, err := CompilePattern(pattern)
+	if err != nil {
+		panic(fmt.Sprintf("compiling pattern %q: %v", pattern, err))
+	}
+	return p
+}
+
+// String returns the pattern literal.
+func (p *Pattern) String() string {
+	return p.raw
+}
+
+// Match tells whether the resource id matches the pattern.
+func (p *Pattern) Match(id ResourceId) bool {
+	segs := idSegments(id)
+	// memo[pi][si] records the result of matching p.segs[pi:] against segs[si:], 0 for unknown, 1 for true and 2 for false.
+	memo := make([][]int8, len(p.segs)+1)
+	for i := range memo {
+		memo[i] = make([]int8, len(segs)+1)
+	}
+
+	var match func(pi, si int) bool
+	match = func(pi, si int) bool {
+		if v := memo[pi][si]; v != 0 {
+			return v == 1
+		}
+		var ok bool
+		switch {
+		case pi == len(p.segs):
+			ok = si == len(segs)
+		case p.segs[pi] == "**":
+			if si == 0 || segs[si-1].boundary {
+				for sj := si; sj <= len(segs) && !ok; sj++ {
+					if sj == 0 || segs[sj-1].boundary {
+						ok = match(pi+1, sj)
+					}
+				}
+			}
+		case si < len(segs):
+			ok = matchSegment(p.segs[pi], segs[si]) && match(pi+1, si+1)
+		}
+		if ok {
+			memo[pi][si] = 1
+		} else {
+			memo[pi][si] = 2
+		}
+		return ok
+	}
+	return match(0, 0)
+}
+
+func matchSegment(pattern string, seg idSegment) bool {
+	if seg.separator {
+		return pattern == "providers"
+	}
+	ok, _ := path.Match(pattern, strings.ToLower(seg.value))
+	return ok
+}
+
+// idSegment is a segment of the resource id literal.
+type idSegment struct {
+	value string
+	// separator is true for the "providers" segment that leads a provider namespace.
+	separator bool
+	// boundary is true if the segments till this one (inclusive) form a resource id, which is the id itself, one of its ancestors or a provider level id.
+	boundary bool
+}
+
+// idSegments returns the segments of the resource id literal, with the leading "/" trimmed.
+func idSegments(id ResourceId) []idSegment {
+	var segs []idSegment
+	appendPairs := func(types, names []string) {
+		for i := range types {
+			segs = append(segs, idSegment{value: types[i]}, idSegment{value: names[i], boundary: true})
+		}
+	}
+	traverseScopes(id, func(id ResourceId) {
+		switch id := id.(type) {
+		case *TenantId:
+		case *SubscriptionId:
+			segs = append(segs, idSegment{value: id.subscriptionsLiteral()}, idSegment{value: id.Id, boundary: true})
+			appendPairs(id.AttrTypes, id.AttrNames)
+		case *ResourceGroup:
+			segs = append(segs,
+				idSegment{value: id.subscriptionsLiteral()}, idSegment{value: id.SubscriptionId, boundary: true},
+				idSegment{value: id.resourceGroupsLiteral()}, idSegment{value: id.Name, boundary: true},
+			)
+			appendPairs(id.AttrTypes, id.AttrNames)
+		case *ManagementGroup:
+			segs = append(segs,
+				idSegment{value: "providers", separator: true}, idSegment{value: id.microsoftManagementLiteral()},
+				idSegment{value: id.managementGroupsLiteral()}, idSegment{value: id.Name, boundary: true},
+			)
+			appendPairs(id.AttrTypes, id.AttrNames)
+		default:
+			segs = append(segs, idSegment{value: "providers", separator: true}, idSegment{value: id.Provider(), boundary: true})
+			appendPairs(id.Types(), id.Names())
+		}
+	})
+	return segs
+}
@@ -0,0 +1,151 @@
+package armid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompilePattern(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{
+			name:  "Valid",
+			input: "/subscriptions/*/resourceGroups/rg-[0-9]/**",
+		},
+		{
+			name:  "Missing leading slash",
+			input: "subscriptions/*",
+			err:   `pattern should start with "/"`,
+		},
+		{
+			name:  "Partial recursive wildcard",
+			input: "/subscriptions/**foo",
+			err:   `"**" should occupy the whole segment, got "**foo"`,
+		},
+		{
+			name:  "Bad pattern",
+			input: "/subscriptions/[a-",
+			err:   `invalid segment "[a-": syntax error in pattern`,
+		},
+		{
+			name:  "Empty segment",
+			input: "/subscriptions//resourceGroups",
+			err:   `empty segment found behind 2th "/"`,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CompilePattern(tt.input)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestPattern_Match(t *testing.T) {
+	cases := []struct {
+		name    string
+		pattern string
+		id      string
+		expect  bool
+	}{
+		{
+			name:    "Segment wildcards",
+			pattern: "/subscriptions/*/resourceGroups/*/providers/Microsoft.Network/virtualNetworks/*/subnets/*",
+			id:      "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet1",
+			expect:  true,
+		},
+		{
+			name:    "Case-insensitive literals",
+			pattern: "/subscriptions/SUB1/resourcegroups/RG-*",
+			id:      "/Subscriptions/sub1/resourceGroups/rg-foo",
+			expect:  true,
+		},
+		{
+			name:    "Segment count mismatch",
+			pattern: "/subscriptions/*/resourceGroups/*/providers/Microsoft.Network/virtualNetworks/*",
+			id:      "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet1",
+			expect:  false,
+		},
+		{
+			name:    "Recursive wildcard matches the scope itself",
+			pattern: "/subscriptions/s1/**",
+			id:      "/subscriptions/s1",
+			expect:  true,
+		},
+		{
+			name:    "Recursive wildcard matches nested scopes",
+			pattern: "/subscriptions/s1/**",
+			id:      "/subscriptions/s1/resourceGroups/rg1/providers/Microsoft.Foo/foos/foo1/providers/Microsoft.Bar/bars/bar1",
+			expect:  true,
+		},
+		{
+			name:    "Recursive wildcard doesn't match other scopes",
+			pattern: "/subscriptions/s1/**",
+			id:      "/subscriptions/s2/resourceGroups/rg1",
+			expect:  false,
+		},
+		{
+			name:    "Recursive wildcard in the middle",
+			pattern: "/**/providers/Microsoft.Authorization/locks/*",
+			id:      "/subscriptions/s1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/providers/Microsoft.Authorization/locks/lock1",
+			expect:  true,
+		},
+		{
+			name:    "Recursive wildcard ends at provider level",
+			pattern: "/subscriptions/s1/**/subnets/*",
+			id:      "/subscriptions/s1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet1",
+			expect:  true,
+		},
+		{
+			name:    "Recursive wildcard honors the boundary",
+			pattern: "/subscriptions/**/subnet1",
+			id:      "/subscriptions/s1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet1",
+			expect:  false,
+		},
+		{
+			name:    "Type matched as name is prevented by boundary",
+			pattern: "/**/vnet1/*",
+			id:      "/subscriptions/s1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet1",
+			expect:  false,
+		},
+		{
+			name:    "Wildcard doesn't match the scope separator",
+			pattern: "/subscriptions/*/*/*",
+			id:      "/subscriptions/s1/providers/Microsoft.Foo",
+			expect:  false,
+		},
+		{
+			name:    "Management group",
+			pattern: "/providers/Microsoft.Management/managementGroups/*",
+			id:      "/providers/microsoft.management/managementgroups/mg1",
+			expect:  true,
+		},
+		{
+			name:    "Tenant",
+			pattern: "/",
+			id:      "/",
+			expect:  true,
+		},
+		{
+			name:    "Recursive wildcard matches tenant",
+			pattern: "/**",
+			id:      "/",
+			expect:  true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := ParseResourceId(tt.id)
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, MustCompilePattern(tt.pattern).Match(id))
+		})
+	}
+}
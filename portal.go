@@ -0,0 +1,152 @@
+package armid
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Endpoints of the Azure portal for the known clouds.
+const (
+	PortalPublic       = "https://portal.azure.com"
+	PortalChina        = "https://portal.azure.cn"
+	PortalUSGovernment = "https://portal.azure.us"
+)
+
+var portalHosts = map[string]Cloud{
+	"portal.azure.com": CloudPublic,
+	"portal.azure.cn":  CloudChina,
+	"portal.azure.us":  CloudUSGovernment,
+}
+
+// PortalEndpoint returns the Azure portal endpoint of the cloud. It is empty for CloudUnknown.
+func (c Cloud) PortalEndpoint() string {
+	switch c {
+	case CloudPublic:
+		return PortalPublic
+	case CloudChina:
+		return PortalChina
+	case CloudUSGovernment:
+		return PortalUSGovernment
+	default:
+		return ""
+	}
+}
+
+// PortalLink is a deep link of the Azure portal to a resource, e.g.
+// "https://portal.azure.com/#@contoso.onmicrosoft.com/resource/subscriptions/0000/resourceGroups/rg1/overview".
+type PortalLink struct {
+	// Id is the resource id that the link points to.
+	Id ResourceId
+
+	// Endpoint is the scheme and host of the portal, e.g. "https://portal.azure.com".
+	// FormatPortalURL defaults it to PortalPublic if empty.
+	Endpoint string
+
+	// Tenant is the tenant domain or id following the "@", e.g. "contoso.onmicrosoft.com". It is optional.
+	Tenant string
+
+	// Blade is the blade segment following the resource id, e.g. "overview". It is optional.
+	// Only a single segment is supported, as a longer blade path is indistinguishable from the resource id.
+	Blade string
+}
+
+// Cloud returns the cloud that the Endpoint belongs to.
+func (l *PortalLink) Cloud() Cloud {
+	host := l.Endpoint
+	if u, err := url.Parse(l.Endpoint); err == nil && u.Host != "" {
+		host = u.Hostname()
+	}
+	return portalHosts[strings.ToLower(host)]
+}
+
+// String returns the portal URL of the link.
+func (l *PortalLink) String() string {
+	endpoint := strings.TrimSuffix(l.Endpoint, "/")
+	if endpoint == "" {
+		endpoint = PortalPublic
+	}
+	builder := strings.Builder{}
+	builder.WriteString(endpoint)
+	builder.WriteString("/#")
+	if l.Tenant != "" {
+		builder.WriteString("@" + l.Tenant + "/")
+	}
+	builder.WriteString("resource")
+	if _, ok := l.Id.(*TenantId); !ok {
+		builder.WriteString(l.Id.String())
+	}
+	if l.Blade != "" {
+		builder.WriteString("/" + l.Blade)
+	}
+	return builder.String()
+}
+
+// FormatPortalURL builds the Azure portal URL of the resource id.
+// The endpoint defaults to PortalPublic if empty. The tenant and blade are optional.
+func FormatPortalURL(id ResourceId, endpoint, tenant, blade string) string {
+	l := PortalLink{
+		Id:       id,
+		Endpoint: endpoint,
+		Tenant:   tenant,
+		Blade:    blade,
+	}
+	return l.String()
+}
+
+// ParsePortalURL parses an Azure portal deep link of a resource into a PortalLink.
+// The trailing segment of the link is regarded as the blade if it can't be parsed as part of the resource id.
+func ParsePortalURL(rawURL string) (*PortalLink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported URL scheme %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("missing host in URL %q", rawURL)
+	}
+	out := &PortalLink{
+		Endpoint: u.Scheme + "://" + u.Host,
+	}
+
+	fragment := u.Fragment
+	if strings.HasPrefix(fragment, "@") {
+		idx := strings.Index(fragment, "/")
+		if idx == -1 {
+			return nil, fmt.Errorf("missing resource path after tenant in URL %q", rawURL)
+		}
+		out.Tenant, fragment = fragment[1:idx], fragment[idx+1:]
+	}
+	if !strings.HasPrefix(fragment, "resource/") && fragment != "resource" {
+		return nil, fmt.Errorf(`URL %q is not a portal link of a resource, which is expected to start with "#resource" or "#@<tenant>/resource"`, rawURL)
+	}
+	path := strings.TrimPrefix(fragment, "resource")
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	if path == "" {
+		path = "/"
+	}
+
+	id, err := ParseResourceId(path)
+	if err != nil {
+		var perr *ParseError
+		if !errors.As(err, &perr) || perr.Kind != ParseErrorMissingResourceName || perr.Offset+len(perr.Segment) != len(path) {
+			return nil, err
+		}
+		blade := perr.Segment
+		path = strings.TrimSuffix(path, "/"+blade)
+		if path == "" {
+			path = "/"
+		}
+		if id, err = ParseResourceId(path); err != nil {
+			return nil, err
+		}
+		out.Blade = blade
+	}
+	out.Id = id
+	return out, nil
+}
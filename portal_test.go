@@ -0,0 +1,84 @@
+package armid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePortalURL(t *testing.T) {
+	cases := []struct {
+		name   string
+		input  string
+		expect *PortalLink
+		cloud  Cloud
+		err    string
+	}{
+		{
+			name:  "With tenant and blade",
+			input: "https://portal.azure.com/#@contoso.onmicrosoft.com/resource/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Foo/foos/foo1/overview",
+			expect: &PortalLink{
+				Id: &ScopedResourceId{
+					AttrParentScope: &ResourceGroup{SubscriptionId: "sub1", Name: "rg1"},
+					AttrProvider:    "Microsoft.Foo",
+					AttrTypes:       []string{"foos"},
+					AttrNames:       []string{"foo1"},
+				},
+				Endpoint: "https://portal.azure.com",
+				Tenant:   "contoso.onmicrosoft.com",
+				Blade:    "overview",
+			},
+			cloud: CloudPublic,
+		},
+		{
+			name:  "Without tenant and blade",
+			input: "https://portal.azure.cn/#resource/subscriptions/sub1/resourceGroups/rg1",
+			expect: &PortalLink{
+				Id:       &ResourceGroup{SubscriptionId: "sub1", Name: "rg1"},
+				Endpoint: "https://portal.azure.cn",
+			},
+			cloud: CloudChina,
+		},
+		{
+			name:  "Blade on resource group",
+			input: "https://portal.azure.us/#@tenant1/resource/subscriptions/sub1/resourceGroups/rg1/deployments",
+			expect: &PortalLink{
+				Id:       &ResourceGroup{SubscriptionId: "sub1", Name: "rg1"},
+				Endpoint: "https://portal.azure.us",
+				Tenant:   "tenant1",
+				Blade:    "deployments",
+			},
+			cloud: CloudUSGovernment,
+		},
+		{
+			name:  "Not a resource link",
+			input: "https://portal.azure.com/#view/HubsExtension/BrowseAll",
+			err:   `URL "https://portal.azure.com/#view/HubsExtension/BrowseAll" is not a portal link of a resource, which is expected to start with "#resource" or "#@<tenant>/resource"`,
+		},
+		{
+			name:  "Invalid resource id",
+			input: "https://portal.azure.com/#resource/subscriptions/sub1/providers/Microsoft.Foo/foos/foo1/providers",
+			err:   "missing provider namespace segment",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := ParsePortalURL(tt.input)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, l)
+			require.Equal(t, tt.cloud, l.Cloud())
+			require.Equal(t, tt.input, l.String())
+		})
+	}
+}
+
+func TestFormatPortalURL(t *testing.T) {
+	id := &ResourceGroup{SubscriptionId: "sub1", Name: "rg1"}
+	require.Equal(t, "https://portal.azure.com/#resource/subscriptions/sub1/resourceGroups/rg1", FormatPortalURL(id, "", "", ""))
+	require.Equal(t, "https://portal.azure.cn/#@contoso.partner.onmschina.cn/resource/subscriptions/sub1/resourceGroups/rg1/overview", FormatPortalURL(id, CloudChina.PortalEndpoint(), "contoso.partner.onmschina.cn", "overview"))
+}
@@ -0,0 +1,134 @@
+package armid
+
+import (
+	"fmt"
+)
+
+// Rebase rewrites the resource id that is under the "from" scope to be under the "to" scope, e.g. to move
+// /subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Foo/foos/foo1 from /subscriptions/sub1/resourceGroups/rg1 to
+// /subscriptions/sub2/resourceGroups/rg2.
+// The "from" must either equal to, or be an ancestor of the resource id. The rewrite is done structurally, so the scopes above the
+// "from" (e.g. the extension scopes), the root scope level AttrTypes and the casing of the builtin literals are kept.
+func Rebase(id, from, to ResourceId) (ResourceId, error) {
+	if !from.Equal(id) && !IsAncestorOf(from, id) {
+		return nil, fmt.Errorf("%q is not under %q", id.String(), from.String())
+	}
+	return rebase(id, from, to)
+}
+
+func rebase(id, from, to ResourceId) (ResourceId, error) {
+	if id.Equal(from) {
+		return keepLiteralOverrides(to.Clone(), id), nil
+	}
+
+	if pid := id.ParentScope(); pid != nil && (pid.Equal(from) || IsAncestorOf(from, pid)) {
+		npid, err := rebase(pid, from, to)
+		if err != nil {
+			return nil, err
+		}
+		if sid, ok := npid.(*ScopedResourceId); ok && len(sid.AttrTypes) == 0 {
+			return nil, fmt.Errorf("provider %q has no resource type", sid.AttrProvider)
+		}
+		out := id.Clone().(*ScopedResourceId)
+		out.AttrParentScope = npid
+		return out, nil
+	}
+
+	// The "from" is within the same provider (or root scope) as the id.
+	switch from := from.(type) {
+	case *TenantId:
+		if _, ok := to.(*TenantId); !ok {
+			return nil, fmt.Errorf("rebasing from the tenant to %q is not supported", to.String())
+		}
+		return id.Clone(), nil
+	case *SubscriptionId:
+		if rg, ok := id.(*ResourceGroup); ok && len(from.AttrTypes) == 0 {
+			sub, ok := to.(*SubscriptionId)
+			if !ok || len(sub.AttrTypes) != 0 {
+				return nil, fmt.Errorf("resource group can only be rebased onto a subscription, got %q", to.String())
+			}
+			out := rg.Clone().(*ResourceGroup)
+			out.SubscriptionId = sub.Id
+			return out, nil
+		}
+	}
+	// The remaining types of the id are appended to the "to" as is, which only makes sense when both are of the same shape.
+	if !from.ScopeEqual(to) {
+		return nil, fmt.Errorf("can't rebase from %q to %q of a different scope", from.String(), to.String())
+	}
+	n := len(from.Names())
+	types, names := id.Types()[n:], id.Names()[n:]
+	out := keepLiteralOverrides(to.Clone(), id)
+	for i := range types {
+		var err error
+		if out, err = Child(out, types[i], names[i]); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// keepLiteralOverrides copies the casing of the builtin literals of the src root scope onto the dst root scope, for the literals that
+// both have. The dst is returned.
+func keepLiteralOverrides(dst, src ResourceId) ResourceId {
+	var subscriptions, resourceGroups string
+	switch src := src.(type) {
+	case *SubscriptionId:
+		subscriptions = src.subscriptionsLiteralOverride
+	case *ResourceGroup:
+		subscriptions, resourceGroups = src.subscriptionsLiteralOverride, src.resourceGroupsLiteralOverride
+	case *ManagementGroup:
+		if dst, ok := dst.(*ManagementGroup); ok {
+			dst.microsoftManagementLiteralOverride = src.microsoftManagementLiteralOverride
+			dst.managementGroupsLiteralOverride = src.managementGroupsLiteralOverride
+		}
+		return dst
+	default:
+		return dst
+	}
+	switch dst := dst.(type) {
+	case *SubscriptionId:
+		dst.subscriptionsLiteralOverride = subscriptions
+	case *ResourceGroup:
+		dst.subscriptionsLiteralOverride = subscriptions
+		if _, ok := src.(*ResourceGroup); ok {
+			dst.resourceGroupsLiteralOverride = resourceGroups
+		}
+	}
+	return dst
+}
+
+// RebaseRule is a scope rewrite rule used by RebaseAll.
+type RebaseRule struct {
+	From ResourceId
+	To   ResourceId
+}
+
+// RebaseAll rebases each of the resource ids by the rule whose From is the deepest one that contains it (i.e. either equal to, or an
+// ancestor of it). The resource ids that match no rule are returned as is (cloned).
+func RebaseAll(ids []ResourceId, rules []RebaseRule) ([]ResourceId, error) {
+	out := make([]ResourceId, 0, len(ids))
+	for _, id := range ids {
+		var rule *RebaseRule
+		depth := -1
+		for i := range rules {
+			r := &rules[i]
+			if !r.From.Equal(id) && !IsAncestorOf(r.From, id) {
+				continue
+			}
+			if d := Depth(r.From); d > depth {
+				rule, depth = r, d
+			}
+		}
+		if rule == nil {
+			out = append(out, id.Clone())
+			continue
+		}
+		nid, err := rebase(id, rule.From, rule.To)
+		if err != nil {
+			return nil, fmt.Errorf("rebasing %q: %v", id.String(), err)
+		}
+		out = append(out, nid)
+	}
+	return out, nil
+}
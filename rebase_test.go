@@ -0,0 +1,169 @@
+package armid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebase(t *testing.T) {
+	cases := []struct {
+		name   string
+		id     string
+		from   string
+		to     string
+		expect string
+		err    string
+	}{
+		{
+			name:   "Resource group",
+			id:     "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet1",
+			from:   "/subscriptions/sub1/resourceGroups/rg1",
+			to:     "/subscriptions/sub2/resourceGroups/rg2",
+			expect: "/subscriptions/sub2/resourceGroups/rg2/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet1",
+		},
+		{
+			name:   "Subscription",
+			id:     "/subscriptions/sub1/resourceGroups/rg1/foos/foo1/providers/Microsoft.Foo/bars/bar1",
+			from:   "/subscriptions/sub1",
+			to:     "/subscriptions/sub2",
+			expect: "/subscriptions/sub2/resourceGroups/rg1/foos/foo1/providers/Microsoft.Foo/bars/bar1",
+		},
+		{
+			name:   "Resource group to management group",
+			id:     "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Foo/foos/foo1",
+			from:   "/subscriptions/sub1/resourceGroups/rg1",
+			to:     "/providers/Microsoft.Management/managementGroups/mg1",
+			expect: "/providers/Microsoft.Management/managementGroups/mg1/providers/Microsoft.Foo/foos/foo1",
+		},
+		{
+			name:   "Parent resource",
+			id:     "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet1/providers/Microsoft.Authorization/locks/lock1",
+			from:   "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1",
+			to:     "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet2",
+			expect: "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet2/subnets/subnet1/providers/Microsoft.Authorization/locks/lock1",
+		},
+		{
+			name:   "Itself",
+			id:     "/subscriptions/sub1/resourceGroups/rg1",
+			from:   "/subscriptions/SUB1/resourceGroups/RG1",
+			to:     "/subscriptions/sub2/resourceGroups/rg2",
+			expect: "/subscriptions/sub2/resourceGroups/rg2",
+		},
+		{
+			name: "Not under",
+			id:   "/subscriptions/sub1/resourceGroups/rg1",
+			from: "/subscriptions/sub2",
+			to:   "/subscriptions/sub3",
+			err:  `"/subscriptions/sub1/resourceGroups/rg1" is not under "/subscriptions/sub2"`,
+		},
+		{
+			name: "Resource group onto non-subscription",
+			id:   "/subscriptions/sub1/resourceGroups/rg1",
+			from: "/subscriptions/sub1",
+			to:   "/providers/Microsoft.Management/managementGroups/mg1",
+			err:  `resource group can only be rebased onto a subscription, got "/providers/Microsoft.Management/managementGroups/mg1"`,
+		},
+		{
+			name: "From tenant",
+			id:   "/subscriptions/sub1",
+			from: "/",
+			to:   "/providers/Microsoft.Management/managementGroups/mg1",
+			err:  `rebasing from the tenant to "/providers/Microsoft.Management/managementGroups/mg1" is not supported`,
+		},
+		{
+			name: "Resource onto different scope",
+			id:   "/subscriptions/sub1/providers/Microsoft.Foo/foos/foo1/bars/bar1",
+			from: "/subscriptions/sub1/providers/Microsoft.Foo/foos/foo1",
+			to:   "/subscriptions/sub2",
+			err:  `can't rebase from "/subscriptions/sub1/providers/Microsoft.Foo/foos/foo1" to "/subscriptions/sub2" of a different scope`,
+		},
+		{
+			name: "Root scope level resource onto different scope",
+			id:   "/subscriptions/sub1/tagNames/tag1",
+			from: "/subscriptions/sub1",
+			to:   "/providers/Microsoft.Management/managementGroups/mg1",
+			err:  `can't rebase from "/subscriptions/sub1" to "/providers/Microsoft.Management/managementGroups/mg1" of a different scope`,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := ParseResourceId(tt.id)
+			require.NoError(t, err)
+			from, err := ParseResourceId(tt.from)
+			require.NoError(t, err)
+			to, err := ParseResourceId(tt.to)
+			require.NoError(t, err)
+			out, err := Rebase(id, from, to)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, out.String())
+			require.Equal(t, tt.id, id.String())
+		})
+	}
+}
+
+func TestRebase_KeepOverrides(t *testing.T) {
+	id, err := ParseResourceId("/subscriptions/sub1/resourceGroups/rg1/foos/foo1/providers/Microsoft.Foo/bars/bar1")
+	require.NoError(t, err)
+	require.NoError(t, id.Normalize("/SUBSCRIPTIONS/resourcegroups/FOOS/microsoft.foo/BARS"))
+	from, err := ParseResourceId("/subscriptions/sub1")
+	require.NoError(t, err)
+	to, err := ParseResourceId("/subscriptions/sub2")
+	require.NoError(t, err)
+	out, err := Rebase(id, from, to)
+	require.NoError(t, err)
+	require.Equal(t, "/SUBSCRIPTIONS/sub2/resourcegroups/rg1/FOOS/foo1/providers/microsoft.foo/BARS/bar1", out.String())
+
+	id, err = ParseResourceId("/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Foo/foos/foo1")
+	require.NoError(t, err)
+	require.NoError(t, id.Normalize("/SUBSCRIPTIONS/RESOURCEGROUPS/Microsoft.Foo/foos"))
+	from, err = ParseResourceId("/subscriptions/sub1/resourceGroups/rg1")
+	require.NoError(t, err)
+	to, err = ParseResourceId("/subscriptions/sub2/resourceGroups/rg2")
+	require.NoError(t, err)
+	out, err = Rebase(id, from, to)
+	require.NoError(t, err)
+	require.Equal(t, "/SUBSCRIPTIONS/sub2/RESOURCEGROUPS/rg2/providers/Microsoft.Foo/foos/foo1", out.String())
+
+	id, err = ParseResourceId("/subscriptions/sub1/resourceGroups/rg1/tagNames/tag1")
+	require.NoError(t, err)
+	require.NoError(t, id.Normalize("/SUBSCRIPTIONS/RESOURCEGROUPS/tagNames"))
+	out, err = Rebase(id, from, to)
+	require.NoError(t, err)
+	require.Equal(t, "/SUBSCRIPTIONS/sub2/RESOURCEGROUPS/rg2/tagNames/tag1", out.String())
+}
+
+func TestRebaseAll(t *testing.T) {
+	parse := func(input string) ResourceId {
+		id, err := ParseResourceId(input)
+		require.NoError(t, err)
+		return id
+	}
+	rules := []RebaseRule{
+		{From: parse("/subscriptions/dev"), To: parse("/subscriptions/prod")},
+		{From: parse("/subscriptions/dev/resourceGroups/rg-dev"), To: parse("/subscriptions/prod/resourceGroups/rg-prod")},
+	}
+	ids := []ResourceId{
+		parse("/subscriptions/dev/resourceGroups/rg-dev/providers/Microsoft.Foo/foos/foo1"),
+		parse("/subscriptions/dev/resourceGroups/rg-shared/providers/Microsoft.Foo/foos/foo1"),
+		parse("/subscriptions/other/resourceGroups/rg-dev"),
+	}
+	out, err := RebaseAll(ids, rules)
+	require.NoError(t, err)
+	var actual []string
+	for _, id := range out {
+		actual = append(actual, id.String())
+	}
+	require.Equal(t, []string{
+		"/subscriptions/prod/resourceGroups/rg-prod/providers/Microsoft.Foo/foos/foo1",
+		"/subscriptions/prod/resourceGroups/rg-shared/providers/Microsoft.Foo/foos/foo1",
+		"/subscriptions/other/resourceGroups/rg-dev",
+	}, actual)
+
+	_, err = RebaseAll(ids[:1], []RebaseRule{{From: parse("/subscriptions/dev"), To: parse("/providers/Microsoft.Management/managementGroups/mg1")}})
+	require.EqualError(t, err, `rebasing "/subscriptions/dev/resourceGroups/rg-dev/providers/Microsoft.Foo/foos/foo1": resource group can only be rebased onto a subscription, got "/providers/Microsoft.Management/managementGroups/mg1"`)
+}
@@ -0,0 +1,159 @@
+package armid
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ResourceType represents a resource type, e.g. "Microsoft.Network/virtualNetworks/subnets".
+type ResourceType struct {
+	// Namespace is the provider namespace, e.g. "Microsoft.Network".
+	Namespace string
+
+	// Types are the type segments, e.g. ["virtualNetworks", "subnets"].
+	// It is empty for the provider itself.
+	Types []string
+
+	// Scope is the optional scope qualifier, which is the resource type that this (extension) resource type is scoped to.
+	// E.g. "Microsoft.Network/virtualNetworks" for "Microsoft.Network/virtualNetworks/providers/Microsoft.Authorization/locks".
+	Scope *ResourceType
+}
+
+// ParseResourceType parses the resource type string literal, e.g. "Microsoft.Network/virtualNetworks/subnets".
+// The scope qualifier is separated by "/providers/", e.g. "Microsoft.Network/virtualNetworks/providers/Microsoft.Authorization/locks".
+func ParseResourceType(input string) (ResourceType, error) {
+	if input == "" {
+		return ResourceType{}, fmt.Errorf("empty resource type")
+	}
+	var levels [][]string
+	var level []string
+	for i, seg := range strings.Split(input, "/") {
+		if seg == "" {
+			if i == 0 {
+				return ResourceType{}, fmt.Errorf(`resource type should not start with "/"`)
+			}
+			return ResourceType{}, fmt.Errorf(`empty segment found behind %dth "/"`, i)
+		}
+		if i != 0 && strings.EqualFold(seg, "providers") {
+			if len(level) < 2 {
+				return ResourceType{}, fmt.Errorf("missing resource type before the %dth segment %q", i+1, seg)
+			}
+			levels = append(levels, level)
+			level = nil
+			continue
+		}
+		level = append(level, seg)
+	}
+	if len(level) == 0 {
+		return ResourceType{}, fmt.Errorf("missing provider namespace after the last %q", "providers")
+	}
+	levels = append(levels, level)
+
+	var scope *ResourceType
+	for _, level := range levels {
+		rt := ResourceType{
+			Namespace: level[0],
+			Types:     level[1:],
+			Scope:     scope,
+		}
+		scope = &rt
+	}
+	return *scope, nil
+}
+
+// ResourceTypeOf returns the resource type of the resource id.
+// For root scopes, the namespace is the builtin provider, e.g. "Microsoft.Resources/subscriptions/resourceGroups" for a resource group.
+// For an extension resource, which is scoped to another resource (rather than a root scope), its Scope is set to the resource type of its parent scope.
+func ResourceTypeOf(id ResourceId) ResourceType {
+	rt := ResourceType{
+		Namespace: id.Provider(),
+		Types:     append([]string{}, id.Types()...),
+	}
+	if pid := id.ParentScope(); pid != nil && !isBareRootScope(pid) {
+		scope := ResourceTypeOf(pid)
+		rt.Scope = &scope
+	}
+	return rt
+}
+
+// String returns the resource type string literal.
+func (t ResourceType) String() string {
+	var prefix string
+	if t.Scope != nil {
+		prefix = t.Scope.String() + "/providers/"
+	}
+	return prefix + strings.Join(append([]string{t.Namespace}, t.Types...), "/")
+}
+
+// Unscoped returns the resource type without the scope qualifier.
+func (t ResourceType) Unscoped() ResourceType {
+	return ResourceType{
+		Namespace: t.Namespace,
+		Types:     append([]string{}, t.Types...),
+	}
+}
+
+// Equal checks the equality of two resource types case-insensitively, including the scope qualifier.
+func (t ResourceType) Equal(o ResourceType) bool {
+	if !t.scopeEqual(o) {
+		return false
+	}
+	return t.unscopedEqual(o)
+}
+
+func (t ResourceType) unscopedEqual(o ResourceType) bool {
+	if !strings.EqualFold(t.Namespace, o.Namespace) {
+		return false
+	}
+	if len(t.Types) != len(o.Types) {
+		return false
+	}
+	for i := range t.Types {
+		if !strings.EqualFold(t.Types[i], o.Types[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t ResourceType) scopeEqual(o ResourceType) bool {
+	if t.Scope == nil || o.Scope == nil {
+		return t.Scope == nil && o.Scope == nil
+	}
+	return t.Scope.Equal(*o.Scope)
+}
+
+// Parent returns the parent resource type within the same provider namespace and scope, e.g. "Microsoft.Network/virtualNetworks" for
+// "Microsoft.Network/virtualNetworks/subnets". The returned bool is false for a top level resource type or a provider.
+func (t ResourceType) Parent() (ResourceType, bool) {
+	if len(t.Types) < 2 {
+		return ResourceType{}, false
+	}
+	return ResourceType{
+		Namespace: t.Namespace,
+		Types:     append([]string{}, t.Types[:len(t.Types)-1]...),
+		Scope:     t.Scope,
+	}, true
+}
+
+// IsChildOf tells whether the resource type is a direct child resource type of the other one.
+func (t ResourceType) IsChildOf(o ResourceType) bool {
+	p, ok := t.Parent()
+	if !ok {
+		return false
+	}
+	return p.Equal(o)
+}
+
+// Child returns the child resource type of the given type name, e.g. "Microsoft.Network/virtualNetworks/subnets" for
+// "Microsoft.Network/virtualNetworks" with "subnets".
+func (t ResourceType) Child(name string) ResourceType {
+	types := make([]string, 0, len(t.Types)+1)
+	types = append(types, t.Types...)
+	types = append(types, name)
+	return ResourceType{
+		Namespace: t.Namespace,
+		Types:     types,
+		Scope:     t.Scope,
+	}
+}
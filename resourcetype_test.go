@@ -0,0 +1,148 @@
+package armid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseResourceType(t *testing.T) {
+	cases := []struct {
+		name   string
+		input  string
+		expect ResourceType
+		err    string
+	}{
+		{
+			name:   "Provider",
+			input:  "Microsoft.Network",
+			expect: ResourceType{Namespace: "Microsoft.Network", Types: []string{}},
+		},
+		{
+			name:   "Child type",
+			input:  "Microsoft.Network/virtualNetworks/subnets",
+			expect: ResourceType{Namespace: "Microsoft.Network", Types: []string{"virtualNetworks", "subnets"}},
+		},
+		{
+			name:  "Scoped type",
+			input: "Microsoft.Network/virtualNetworks/PROVIDERS/Microsoft.Authorization/locks",
+			expect: ResourceType{
+				Namespace: "Microsoft.Authorization",
+				Types:     []string{"locks"},
+				Scope:     &ResourceType{Namespace: "Microsoft.Network", Types: []string{"virtualNetworks"}},
+			},
+		},
+		{
+			name:  "Empty",
+			input: "",
+			err:   "empty resource type",
+		},
+		{
+			name:  "Leading slash",
+			input: "/Microsoft.Network",
+			err:   `resource type should not start with "/"`,
+		},
+		{
+			name:  "Empty segment",
+			input: "Microsoft.Network//subnets",
+			err:   `empty segment found behind 1th "/"`,
+		},
+		{
+			name:  "Scope without type",
+			input: "Microsoft.Network/providers/Microsoft.Authorization/locks",
+			err:   `missing resource type before the 2th segment "providers"`,
+		},
+		{
+			name:  "Missing namespace after scope",
+			input: "Microsoft.Network/virtualNetworks/providers",
+			err:   `missing provider namespace after the last "providers"`,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			rt, err := ParseResourceType(tt.input)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, rt)
+			rt2, err := ParseResourceType(rt.String())
+			require.NoError(t, err)
+			require.True(t, rt.Equal(rt2))
+		})
+	}
+}
+
+func TestResourceTypeOf(t *testing.T) {
+	cases := []struct {
+		name   string
+		input  string
+		expect string
+	}{
+		{
+			name:   "Resource group",
+			input:  "/subscriptions/sub1/resourceGroups/rg1",
+			expect: "Microsoft.Resources/subscriptions/resourceGroups",
+		},
+		{
+			name:   "Management group",
+			input:  "/providers/Microsoft.Management/managementGroups/mg1",
+			expect: "Microsoft.Management/managementGroups",
+		},
+		{
+			name:   "Resource",
+			input:  "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet1",
+			expect: "Microsoft.Network/virtualNetworks/subnets",
+		},
+		{
+			name:   "Extension resource",
+			input:  "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/providers/Microsoft.Authorization/locks/lock1",
+			expect: "Microsoft.Network/virtualNetworks/providers/Microsoft.Authorization/locks",
+		},
+		{
+			name:   "Extension resource on root scope",
+			input:  "/subscriptions/sub1/providers/Microsoft.Authorization/locks/lock1",
+			expect: "Microsoft.Authorization/locks",
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := ParseResourceId(tt.input)
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, ResourceTypeOf(id).String())
+		})
+	}
+}
+
+func TestResourceType_Hierarchy(t *testing.T) {
+	vnet, err := ParseResourceType("Microsoft.Network/virtualNetworks")
+	require.NoError(t, err)
+	subnet, err := ParseResourceType("MICROSOFT.NETWORK/VIRTUALNETWORKS/SUBNETS")
+	require.NoError(t, err)
+
+	require.True(t, vnet.Child("subnets").Equal(subnet))
+	require.True(t, subnet.IsChildOf(vnet))
+	require.False(t, vnet.IsChildOf(subnet))
+	require.False(t, subnet.Child("foos").IsChildOf(vnet))
+
+	p, ok := subnet.Parent()
+	require.True(t, ok)
+	require.True(t, p.Equal(vnet))
+	_, ok = vnet.Parent()
+	require.False(t, ok)
+
+	lock, err := ParseResourceType("Microsoft.Network/virtualNetworks/providers/Microsoft.Authorization/locks")
+	require.NoError(t, err)
+	rgLock, err := ParseResourceType("Microsoft.Authorization/locks")
+	require.NoError(t, err)
+	require.False(t, lock.Equal(rgLock))
+	require.True(t, lock.Unscoped().Equal(rgLock))
+
+	// Child doesn't alias the receiver's types
+	base := ResourceType{Namespace: "Microsoft.Foo", Types: make([]string, 1, 4)}
+	base.Types[0] = "foos"
+	c1, c2 := base.Child("bars"), base.Child("bazs")
+	require.Equal(t, "Microsoft.Foo/foos/bars", c1.String())
+	require.Equal(t, "Microsoft.Foo/foos/bazs", c2.String())
+}
@@ -0,0 +1,239 @@
+package armid
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// RouteRegistry is a case-insensitive trie of the known resource id shapes (i.e. ScopeString), which is built from Swagger-style path templates.
+// It is used to normalize the casing of the invariant parts (e.g. provider, types) of resource ids in bulk.
+//
+// The templates with a leading scope placeholder (e.g. "/{scope}/providers/Microsoft.Authorization/locks/{lockName}") are registered as
+// extension routes, which can be nested under any known shape.
+type RouteRegistry struct {
+	// root is the trie of the full shapes, starting from the root scope.
+	root *routeNode
+	// ext is the trie of the extension shapes, starting from any known shape.
+	ext *routeNode
+}
+
+type routeNode struct {
+	// literal is the canonical casing of the segment leading to this node
+	literal  string
+	children map[string]*routeNode
+	// terminal indicates the segments till this node form a known shape
+	terminal bool
+}
+
+// routeLevelSeparator separates the levels of a shape in the trie. It never appears in a segment.
+const routeLevelSeparator = "/"
+
+func newRouteNode(literal string) *routeNode {
+	return &routeNode{literal: literal, children: map[string]*routeNode{}}
+}
+
+func (n *routeNode) child(seg string) *routeNode {
+	return n.children[strings.ToLower(seg)]
+}
+
+func (n *routeNode) insert(seg string) *routeNode {
+	key := strings.ToLower(seg)
+	c, ok := n.children[key]
+	if !ok {
+		c = newRouteNode(seg)
+		n.children[key] = c
+	}
+	return c
+}
+
+// insertLevels inserts the levels into the trie, marking the end of the last level as terminal.
+func (n *routeNode) insertLevels(levels [][]string, scopedFrom int) {
+	node := n
+	for i, level := range levels {
+		for j, seg := range level {
+			node = node.insert(seg)
+			// Each scoped level's provider level shape is regarded as known
+			if j == 0 && i >= scopedFrom {
+				node.insert(routeLevelSeparator).terminal = true
+			}
+		}
+		node = node.insert(routeLevelSeparator)
+	}
+	node.terminal = true
+}
+
+// NewRouteRegistry creates a RouteRegistry, which knows the shapes of the root scopes.
+func NewRouteRegistry() *RouteRegistry {
+	r := &RouteRegistry{
+		root: newRouteNode(""),
+		ext:  newRouteNode(""),
+	}
+	for _, id := range []ResourceId{&TenantId{}, &SubscriptionId{}, &ResourceGroup{}, &ManagementGroup{}} {
+		r.root.insertLevels(routeLevels(id), 1)
+	}
+	return r
+}
+
+// Add registers the Swagger-style path templates, e.g.
+// "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Network/virtualNetworks/{virtualNetworkName}".
+// The template must be a resource id shape, i.e. a collection or action path is not allowed, and the placeholders (other than the scope
+// placeholder) must be at the resource name positions.
+func (r *RouteRegistry) Add(templates ...string) error {
+	for _, template := range templates {
+		if err := r.add(template); err != nil {
+			return fmt.Errorf("adding template %q: %v", template, err)
+		}
+	}
+	return nil
+}
+
+func (r *RouteRegistry) add(template string) error {
+	t, err := CompileTemplate(template)
+	if err != nil {
+		return err
+	}
+	scopeParam := t.ScopeParam()
+	// Each placeholder is given a distinct value, so that we can tell where it lands in the rendered id.
+	params := map[string]string{}
+	for i, name := range t.Params() {
+		params[name] = fmt.Sprintf("armid-placeholder-%d", i)
+	}
+	if scopeParam != "" {
+		params[scopeParam] = "subscriptions/x"
+	}
+	id, err := t.Render(params)
+	if err != nil {
+		return err
+	}
+	names := map[string]bool{}
+	traverseScopes(id, func(id ResourceId) {
+		for _, name := range id.Names() {
+			names[name] = true
+		}
+	})
+	for _, name := range t.Params() {
+		if name != scopeParam && !names[params[name]] {
+			return fmt.Errorf("placeholder %q is not at a resource name position", name)
+		}
+	}
+	levels := routeLevels(id)
+	if scopeParam == "" {
+		r.root.insertLevels(levels, 1)
+		return nil
+	}
+	if len(levels) < 2 {
+		return fmt.Errorf("missing route after the scope placeholder")
+	}
+	r.ext.insertLevels(levels[1:], 0)
+	return nil
+}
+
+// LoadOpenAPI registers the keys of the "paths" and "x-ms-paths" of the OpenAPI (Swagger) JSON files as templates.
+// The paths that are not resource id shapes (e.g. collection or action paths), or have placeholders at non-name positions, are skipped.
+func (r *RouteRegistry) LoadOpenAPI(files ...string) error {
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		var doc struct {
+			Paths    map[string]json.RawMessage `json:"paths"`
+			XMsPaths map[string]json.RawMessage `json:"x-ms-paths"`
+		}
+		if err := json.Unmarshal(b, &doc); err != nil {
+			return fmt.Errorf("unmarshalling %s: %v", file, err)
+		}
+		for _, paths := range []map[string]json.RawMessage{doc.Paths, doc.XMsPaths} {
+			for path := range paths {
+				// The x-ms-paths can contain query parameters to distinguish overloaded paths
+				if idx := strings.Index(path, "?"); idx != -1 {
+					path = path[:idx]
+				}
+				_ = r.add(path)
+			}
+		}
+	}
+	return nil
+}
+
+// UnknownShapeError is the error returned by RouteRegistry.Normalize when the shape of the resource id is not registered.
+type UnknownShapeError struct {
+	// Id is the resource id literal.
+	Id string
+	// ScopeString is the scope string of the resource id.
+	ScopeString string
+}
+
+func (e *UnknownShapeError) Error() string {
+	return fmt.Sprintf("unknown shape %q of id %q", e.ScopeString, e.Id)
+}
+
+// Normalize normalizes the casing of the invariant parts of the resource id in place, based on the registered shapes.
+// A *UnknownShapeError is returned if the shape of the id is not registered.
+func (r *RouteRegistry) Normalize(id ResourceId) error {
+	levels := routeLevels(id)
+	canonical, ok := r.lookup(r.root, levels, nil)
+	if !ok {
+		return &UnknownShapeError{Id: id.String(), ScopeString: id.ScopeString()}
+	}
+	var scopeStr string
+	for _, level := range canonical {
+		if len(level) != 0 {
+			scopeStr += "/" + strings.Join(level, "/")
+		}
+	}
+	if scopeStr == "" {
+		scopeStr = id.ScopeString()
+	}
+	return id.Normalize(scopeStr)
+}
+
+// NormalizeAll normalizes the resource ids in place, and returns the ones whose shape is unknown.
+func (r *RouteRegistry) NormalizeAll(ids []ResourceId) (unknown []ResourceId) {
+	for _, id := range ids {
+		if err := r.Normalize(id); err != nil {
+			unknown = append(unknown, id)
+		}
+	}
+	return unknown
+}
+
+// lookup matches the levels from the node, and returns the canonical levels if matched.
+// When a known shape is reached in the middle, the remaining levels are allowed to be matched by the extension routes.
+func (r *RouteRegistry) lookup(node *routeNode, levels [][]string, out [][]string) ([][]string, bool) {
+	if len(levels) == 0 {
+		return out, node.terminal
+	}
+	if node.terminal && len(out) != 0 {
+		if canonical, ok := r.lookup(r.ext, levels, out); ok {
+			return canonical, true
+		}
+	}
+	level := make([]string, 0, len(levels[0]))
+	for _, seg := range levels[0] {
+		if node = node.child(seg); node == nil {
+			return nil, false
+		}
+		level = append(level, node.literal)
+	}
+	if node = node.child(routeLevelSeparator); node == nil {
+		return nil, false
+	}
+	return r.lookup(node, levels[1:], append(out[:len(out):len(out)], level))
+}
+
+// routeLevels splits the scope string of the resource id into levels, one for each scope, from the root scope to the route scope.
+// The level of the tenant root scope is empty.
+func routeLevels(id ResourceId) [][]string {
+	var levels [][]string
+	traverseScopes(id, func(id ResourceId) {
+		if _, ok := id.(*TenantId); ok {
+			levels = append(levels, []string{})
+			return
+		}
+		levels = append(levels, strings.Split(strings.TrimPrefix(id.RouteScopeString(), "/"), "/"))
+	})
+	return levels
+}
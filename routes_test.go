@@ -0,0 +1,142 @@
+package armid
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteRegistry_Normalize(t *testing.T) {
+	r := NewRouteRegistry()
+	require.NoError(t, r.Add(
+		"/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Network/virtualNetworks/{virtualNetworkName}",
+		"/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Network/virtualNetworks/{virtualNetworkName}/subnets/{subnetName}",
+		"/subscriptions/{subscriptionId}/tagNames/{tagName}",
+		"/{scope}/providers/Microsoft.Authorization/locks/{lockName}",
+		"/providers/Microsoft.Management/managementGroups/{groupId}/providers/Microsoft.Authorization/policyDefinitions/{policyDefinitionName}",
+	))
+
+	cases := []struct {
+		name    string
+		input   string
+		expect  string
+		unknown bool
+	}{
+		{
+			name:   "Tenant",
+			input:  "/",
+			expect: "/",
+		},
+		{
+			name:   "Resource group",
+			input:  "/SUBSCRIPTIONS/sub1/RESOURCEGROUPS/rg1",
+			expect: "/subscriptions/sub1/resourceGroups/rg1",
+		},
+		{
+			name:   "Root scope level resource",
+			input:  "/subscriptions/sub1/TAGNAMES/tag1",
+			expect: "/subscriptions/sub1/tagNames/tag1",
+		},
+		{
+			name:   "Child resource",
+			input:  "/subscriptions/sub1/resourcegroups/rg1/providers/microsoft.network/VIRTUALNETWORKS/vnet1/SUBNETS/subnet1",
+			expect: "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet1",
+		},
+		{
+			name:   "Provider level",
+			input:  "/subscriptions/sub1/resourcegroups/rg1/providers/microsoft.network",
+			expect: "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network",
+		},
+		{
+			name:   "Extension on root scope",
+			input:  "/subscriptions/sub1/resourcegroups/rg1/providers/microsoft.authorization/LOCKS/lock1",
+			expect: "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Authorization/locks/lock1",
+		},
+		{
+			name:   "Extension on resource",
+			input:  "/subscriptions/sub1/resourcegroups/rg1/providers/microsoft.network/virtualnetworks/vnet1/providers/microsoft.authorization/locks/lock1",
+			expect: "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/providers/Microsoft.Authorization/locks/lock1",
+		},
+		{
+			name:   "Nested extensions",
+			input:  "/providers/microsoft.management/managementgroups/mg1/providers/microsoft.authorization/policydefinitions/def1/providers/microsoft.authorization/locks/lock1",
+			expect: "/providers/Microsoft.Management/managementGroups/mg1/providers/Microsoft.Authorization/policyDefinitions/def1/providers/Microsoft.Authorization/locks/lock1",
+		},
+		{
+			name:    "Unknown type",
+			input:   "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/publicIPAddresses/pip1",
+			unknown: true,
+		},
+		{
+			name:    "Extension on unknown resource",
+			input:   "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Compute/virtualMachines/vm1/providers/Microsoft.Authorization/locks/lock1",
+			unknown: true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := ParseResourceId(tt.input)
+			require.NoError(t, err)
+			err = r.Normalize(id)
+			if tt.unknown {
+				var uerr *UnknownShapeError
+				require.True(t, errors.As(err, &uerr), err)
+				require.Equal(t, tt.input, uerr.Id)
+				require.Equal(t, tt.input, id.String())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, id.String())
+		})
+	}
+}
+
+func TestRouteRegistry_Add_Error(t *testing.T) {
+	r := NewRouteRegistry()
+	err := r.Add("/subscriptions/{subscriptionId}/providers/{resourceProviderNamespace}/{resourceType}/{resourceName}")
+	require.EqualError(t, err, `adding template "/subscriptions/{subscriptionId}/providers/{resourceProviderNamespace}/{resourceType}/{resourceName}": placeholder "resourceProviderNamespace" is not at a resource name position`)
+	err = r.Add("/{scope}/providers/Microsoft.Authorization/{lockType}/{lockName}")
+	require.EqualError(t, err, `adding template "/{scope}/providers/Microsoft.Authorization/{lockType}/{lockName}": placeholder "lockType" is not at a resource name position`)
+}
+
+func TestRouteRegistry_LoadOpenAPI(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "spec.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{
+  "swagger": "2.0",
+  "paths": {
+    "/subscriptions/{subscriptionId}/providers/Microsoft.Foo/foos": {},
+    "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Foo/foos/{fooName}": {},
+    "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Foo/foos/{fooName}/restart": {},
+    "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/{resourceProviderNamespace}/{resourceType}/{resourceName}": {}
+  },
+  "x-ms-paths": {
+    "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Foo/foos/{fooName}/bars/{barName}?api-version=2020-01-01": {}
+  }
+}`), 0644))
+
+	r := NewRouteRegistry()
+	require.NoError(t, r.LoadOpenAPI(file))
+
+	var ids []ResourceId
+	for _, input := range []string{
+		"/subscriptions/sub1/resourceGroups/rg1/providers/microsoft.foo/FOOS/foo1",
+		"/subscriptions/sub1/resourceGroups/rg1/providers/microsoft.foo/FOOS/foo1/BARS/bar1",
+		"/subscriptions/sub1/resourceGroups/rg1/providers/microsoft.foo/BAZS/baz1",
+		"/subscriptions/sub1/resourceGroups/rg1/providers/X/Y/z",
+	} {
+		id, err := ParseResourceId(input)
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+	unknown := r.NormalizeAll(ids)
+	require.Equal(t, []ResourceId{ids[2], ids[3]}, unknown)
+	require.Equal(t, "/subscriptions/sub1/resourceGroups/rg1/providers/X/Y/z", ids[3].String())
+	require.Equal(t, "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Foo/foos/foo1", ids[0].String())
+	require.Equal(t, "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Foo/foos/foo1/bars/bar1", ids[1].String())
+
+	require.Error(t, r.LoadOpenAPI(filepath.Join(dir, "not-exist.json")))
+}
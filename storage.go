@@ -0,0 +1,61 @@
+package armid
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+var (
+	_ sql.Scanner   = &ID{}
+	_ driver.Valuer = ID{}
+)
+
+// Scan implements sql.Scanner. A NULL or empty string value results into the zero value.
+func (i *ID) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*i = ID{}
+		return nil
+	case string:
+		return i.UnmarshalText([]byte(src))
+	case []byte:
+		return i.UnmarshalText(src)
+	default:
+		return fmt.Errorf("unsupported type %T to scan into a resource id", src)
+	}
+}
+
+// Value implements driver.Valuer. The zero value results into NULL.
+func (i ID) Value() (driver.Value, error) {
+	if i.id == nil {
+		return nil, nil
+	}
+	return i.String(), nil
+}
+
+// StorageKey returns the canonical storage key of the resource id, which is suitable to be used as the (indexed) key in a database.
+// The key consists of the lowercased and path escaped segments of the resource id (including the "providers" segments), each is led by "/",
+// and ends with a trailing "/", e.g. "/subscriptions/0000/resourcegroups/rg1/".
+// The key of an ancestor is always a prefix of the keys of its descendants, so that all of them form a contiguous key range,
+// see KeyRange.
+func StorageKey(id ResourceId) string {
+	var sb strings.Builder
+	sb.WriteString("/")
+	for _, seg := range idSegments(id) {
+		sb.WriteString(url.PathEscape(strings.ToLower(seg.value)))
+		sb.WriteString("/")
+	}
+	return sb.String()
+}
+
+// KeyRange returns the [start, end) range of the storage keys of the resource id and all its descendants, which can be used to
+// query by e.g. "key >= start AND key < end".
+func KeyRange(id ResourceId) (start, end string) {
+	start = StorageKey(id)
+	// The key always ends with "/", whose successor is "0".
+	end = start[:len(start)-1] + "0"
+	return start, end
+}
@@ -0,0 +1,103 @@
+package armid
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestID_SQL(t *testing.T) {
+	var id ID
+	require.NoError(t, id.Scan("/subscriptions/sub1/resourceGroups/rg1"))
+	require.Equal(t, "/subscriptions/sub1/resourceGroups/rg1", id.String())
+	v, err := id.Value()
+	require.NoError(t, err)
+	require.Equal(t, "/subscriptions/sub1/resourceGroups/rg1", v)
+
+	require.NoError(t, id.Scan([]byte("/subscriptions/sub1")))
+	require.Equal(t, "/subscriptions/sub1", id.String())
+
+	require.NoError(t, id.Scan(nil))
+	require.True(t, id.IsZero())
+	v, err = id.Value()
+	require.NoError(t, err)
+	require.Nil(t, v)
+
+	require.EqualError(t, id.Scan(1), "unsupported type int to scan into a resource id")
+	require.EqualError(t, id.Scan("foo"), `id should start with "/"`)
+}
+
+func TestStorageKey(t *testing.T) {
+	cases := []struct {
+		input  string
+		expect string
+	}{
+		{
+			input:  "/",
+			expect: "/",
+		},
+		{
+			input:  "/SUBSCRIPTIONS/Sub1/resourceGroups/RG1",
+			expect: "/subscriptions/sub1/resourcegroups/rg1/",
+		},
+		{
+			input:  "/providers/Microsoft.Management/managementGroups/mg1/providers/Microsoft.Foo/foos/a b%c",
+			expect: "/providers/microsoft.management/managementgroups/mg1/providers/microsoft.foo/foos/a%20b%25c/",
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.input, func(t *testing.T) {
+			id, err := ParseResourceId(tt.input)
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, StorageKey(id))
+		})
+	}
+}
+
+func TestKeyRange(t *testing.T) {
+	inputs := []string{
+		"/subscriptions/sub1",
+		"/subscriptions/sub1/resourceGroups/rg1",
+		"/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Foo/foos/foo1",
+		"/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Foo/foos/foo1/providers/Microsoft.Bar/bars/bar1",
+		"/subscriptions/sub1/resourceGroups/rg1-other",
+		"/subscriptions/sub1/resourceGroups/rg1-other/providers/Microsoft.Foo/foos/foo1",
+		"/subscriptions/sub1/resourceGroups/rg10",
+		"/subscriptions/sub1/resourceGroups/rg2/providers/Microsoft.Foo/foos/foo1",
+		"/subscriptions/sub2/resourceGroups/rg1",
+		"/providers/Microsoft.Management/managementGroups/mg1",
+	}
+	var keys []string
+	for _, input := range inputs {
+		id, err := ParseResourceId(input)
+		require.NoError(t, err)
+		keys = append(keys, StorageKey(id))
+	}
+	sort.Strings(keys)
+
+	base, err := ParseResourceId("/subscriptions/SUB1/resourceGroups/RG1")
+	require.NoError(t, err)
+	start, end := KeyRange(base)
+	require.Equal(t, "/subscriptions/sub1/resourcegroups/rg1/", start)
+	require.Equal(t, "/subscriptions/sub1/resourcegroups/rg10", end)
+
+	var inRange []string
+	for _, k := range keys {
+		if k >= start && k < end {
+			inRange = append(inRange, k)
+		}
+	}
+	require.Equal(t, []string{
+		"/subscriptions/sub1/resourcegroups/rg1/",
+		"/subscriptions/sub1/resourcegroups/rg1/providers/microsoft.foo/foos/foo1/",
+		"/subscriptions/sub1/resourcegroups/rg1/providers/microsoft.foo/foos/foo1/providers/microsoft.bar/bars/bar1/",
+	}, inRange)
+
+	start, end = KeyRange(&TenantId{})
+	require.Equal(t, "/", start)
+	require.Equal(t, "0", end)
+	for _, k := range keys {
+		require.True(t, k >= start && k < end)
+	}
+}
@@ -0,0 +1,190 @@
+package armid
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ResourceTypeKind classifies a resource type by how it is managed by ARM.
+type ResourceTypeKind int
+
+const (
+	// ResourceTypeKindProxy is a resource type without location, whose lifecycle is managed by the resource provider.
+	ResourceTypeKindProxy ResourceTypeKind = iota + 1
+	// ResourceTypeKindTracked is a resource type with location, which is tracked by ARM.
+	ResourceTypeKindTracked
+	// ResourceTypeKindExtension is a resource type that extends another resource, e.g. "Microsoft.Authorization/locks".
+	ResourceTypeKindExtension
+)
+
+func (k ResourceTypeKind) String() string {
+	switch k {
+	case ResourceTypeKindProxy:
+		return "Proxy"
+	case ResourceTypeKindTracked:
+		return "Tracked"
+	case ResourceTypeKindExtension:
+		return "Extension"
+	default:
+		return fmt.Sprintf("ResourceTypeKind(%d)", int(k))
+	}
+}
+
+// ResourceTypeInfo is the metadata of a resource type, as registered by its resource provider.
+type ResourceTypeInfo struct {
+	// Namespace is the provider namespace in its canonical casing, e.g. "Microsoft.Network".
+	Namespace string
+
+	// ResourceType is the resource type without the provider namespace in its canonical casing, e.g. "virtualNetworks/subnets".
+	ResourceType string
+
+	// APIVersions are the supported API versions.
+	APIVersions []string
+
+	// Locations are the supported locations.
+	Locations []string
+
+	// Capabilities are the capabilities of the resource type, e.g. "SupportsTags".
+	Capabilities []string
+
+	// Kind is the kind of this resource type. It is inferred from the capabilities and the locations:
+	// The type supporting extension is an extension resource type, otherwise it is tracked if it supports location, or proxy if not.
+	Kind ResourceTypeKind
+}
+
+// TypeString returns the resource type string literal in its canonical casing, which is in the same form as ResourceId.TypeString().
+func (info *ResourceTypeInfo) TypeString() string {
+	return info.Namespace + "/" + info.ResourceType
+}
+
+// TypeRegistry is a registry of resource type metadata, indexed by the type string case-insensitively.
+// It is loaded from the offline dumps of the resource providers, e.g. the output of `az provider list`.
+type TypeRegistry struct {
+	types map[string]*ResourceTypeInfo
+}
+
+// NewTypeRegistry creates an empty TypeRegistry.
+func NewTypeRegistry() *TypeRegistry {
+	return &TypeRegistry{types: map[string]*ResourceTypeInfo{}}
+}
+
+// LoadTypeRegistryFiles creates a TypeRegistry from the provider dump files.
+func LoadTypeRegistryFiles(files ...string) (*TypeRegistry, error) {
+	r := NewTypeRegistry()
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		err = r.Load(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("loading %s: %v", file, err)
+		}
+	}
+	return r, nil
+}
+
+type providerDump struct {
+	Namespace     string `json:"namespace"`
+	ResourceTypes []struct {
+		ResourceType string   `json:"resourceType"`
+		Locations    []string `json:"locations"`
+		APIVersions  []string `json:"apiVersions"`
+		Capabilities string   `json:"capabilities"`
+	} `json:"resourceTypes"`
+}
+
+// Load loads the providers from the reader into the registry, which can be either the JSON array output by `az provider list`,
+// the JSON object output by `az provider show`, or the response of the ARM "Providers - List" API (i.e. a JSON object with a "value" array).
+// The types loaded later override the existing ones.
+func (r *TypeRegistry) Load(reader io.Reader) error {
+	b, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	var providers []providerDump
+	switch trimmed := strings.TrimSpace(string(b)); {
+	case strings.HasPrefix(trimmed, "["):
+		if err := json.Unmarshal(b, &providers); err != nil {
+			return err
+		}
+	case strings.HasPrefix(trimmed, "{"):
+		var obj struct {
+			Value *[]providerDump `json:"value"`
+			providerDump
+		}
+		if err := json.Unmarshal(b, &obj); err != nil {
+			return err
+		}
+		if obj.Value != nil {
+			providers = *obj.Value
+		} else {
+			providers = []providerDump{obj.providerDump}
+		}
+	default:
+		return fmt.Errorf("expect a JSON array or object")
+	}
+
+	for _, p := range providers {
+		if p.Namespace == "" {
+			return fmt.Errorf("missing provider namespace")
+		}
+		for _, rt := range p.ResourceTypes {
+			info := &ResourceTypeInfo{
+				Namespace:    p.Namespace,
+				ResourceType: rt.ResourceType,
+				APIVersions:  rt.APIVersions,
+				Locations:    rt.Locations,
+			}
+			for _, c := range strings.Split(rt.Capabilities, ",") {
+				if c = strings.TrimSpace(c); c != "" {
+					info.Capabilities = append(info.Capabilities, c)
+				}
+			}
+			info.Kind = inferResourceTypeKind(info)
+			r.types[strings.ToLower(info.TypeString())] = info
+		}
+	}
+	return nil
+}
+
+func inferResourceTypeKind(info *ResourceTypeInfo) ResourceTypeKind {
+	supportsLocation := len(info.Locations) != 0
+	for _, c := range info.Capabilities {
+		if strings.EqualFold(c, "SupportsExtension") {
+			return ResourceTypeKindExtension
+		}
+		if strings.EqualFold(c, "SupportsLocation") {
+			supportsLocation = true
+		}
+	}
+	if supportsLocation {
+		return ResourceTypeKindTracked
+	}
+	return ResourceTypeKindProxy
+}
+
+// Add adds the resource type metadata into the registry, overriding the existing one of the same type.
+func (r *TypeRegistry) Add(info *ResourceTypeInfo) {
+	r.types[strings.ToLower(info.TypeString())] = info
+}
+
+// Len returns the number of the registered resource types.
+func (r *TypeRegistry) Len() int {
+	return len(r.types)
+}
+
+// LookupType looks up the metadata of the resource type string (e.g. "Microsoft.Network/virtualNetworks") case-insensitively.
+func (r *TypeRegistry) LookupType(typeString string) (*ResourceTypeInfo, bool) {
+	info, ok := r.types[strings.ToLower(typeString)]
+	return info, ok
+}
+
+// Lookup looks up the metadata of the resource type of the id, i.e. id.TypeString().
+func (r *TypeRegistry) Lookup(id ResourceId) (*ResourceTypeInfo, bool) {
+	return r.LookupType(id.TypeString())
+}
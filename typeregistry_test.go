@@ -0,0 +1,180 @@
+package armid
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const azProviderList = `[
+  {
+    "id": "/subscriptions/sub1/providers/Microsoft.Network",
+    "namespace": "Microsoft.Network",
+    "registrationState": "Registered",
+    "resourceTypes": [
+      {
+        "resourceType": "virtualNetworks",
+        "locations": ["West US", "East US"],
+        "apiVersions": ["2023-05-01", "2022-01-01"],
+        "capabilities": "CrossResourceGroupResourceMove, SupportsTags, SupportsLocation"
+      },
+      {
+        "resourceType": "virtualNetworks/subnets",
+        "locations": [],
+        "apiVersions": ["2023-05-01"]
+      }
+    ]
+  },
+  {
+    "namespace": "Microsoft.Authorization",
+    "resourceTypes": [
+      {
+        "resourceType": "locks",
+        "locations": [],
+        "apiVersions": ["2020-05-01"],
+        "capabilities": "SupportsExtension"
+      }
+    ]
+  },
+  {
+    "namespace": "Microsoft.Resources",
+    "resourceTypes": [
+      {
+        "resourceType": "subscriptions/resourceGroups",
+        "locations": ["West US"],
+        "apiVersions": ["2021-04-01"]
+      }
+    ]
+  }
+]`
+
+func TestTypeRegistry(t *testing.T) {
+	r := NewTypeRegistry()
+	require.NoError(t, r.Load(strings.NewReader(azProviderList)))
+	require.Equal(t, 4, r.Len())
+
+	cases := []struct {
+		name   string
+		id     string
+		expect *ResourceTypeInfo
+	}{
+		{
+			name: "Tracked",
+			id:   "/subscriptions/sub1/resourceGroups/rg1/providers/microsoft.network/VIRTUALNETWORKS/vnet1",
+			expect: &ResourceTypeInfo{
+				Namespace:    "Microsoft.Network",
+				ResourceType: "virtualNetworks",
+				APIVersions:  []string{"2023-05-01", "2022-01-01"},
+				Locations:    []string{"West US", "East US"},
+				Capabilities: []string{"CrossResourceGroupResourceMove", "SupportsTags", "SupportsLocation"},
+				Kind:         ResourceTypeKindTracked,
+			},
+		},
+		{
+			name: "Proxy",
+			id:   "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet1",
+			expect: &ResourceTypeInfo{
+				Namespace:    "Microsoft.Network",
+				ResourceType: "virtualNetworks/subnets",
+				APIVersions:  []string{"2023-05-01"},
+				Locations:    []string{},
+				Kind:         ResourceTypeKindProxy,
+			},
+		},
+		{
+			name: "Extension",
+			id:   "/subscriptions/sub1/providers/Microsoft.Authorization/locks/lock1",
+			expect: &ResourceTypeInfo{
+				Namespace:    "Microsoft.Authorization",
+				ResourceType: "locks",
+				APIVersions:  []string{"2020-05-01"},
+				Locations:    []string{},
+				Capabilities: []string{"SupportsExtension"},
+				Kind:         ResourceTypeKindExtension,
+			},
+		},
+		{
+			name: "Root scope",
+			id:   "/subscriptions/sub1/resourceGroups/rg1",
+			expect: &ResourceTypeInfo{
+				Namespace:    "Microsoft.Resources",
+				ResourceType: "subscriptions/resourceGroups",
+				APIVersions:  []string{"2021-04-01"},
+				Locations:    []string{"West US"},
+				Kind:         ResourceTypeKindTracked,
+			},
+		},
+		{
+			name: "Unknown",
+			id:   "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Compute/virtualMachines/vm1",
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := ParseResourceId(tt.id)
+			require.NoError(t, err)
+			info, ok := r.Lookup(id)
+			if tt.expect == nil {
+				require.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			require.Equal(t, tt.expect, info)
+		})
+	}
+}
+
+func TestTypeRegistry_Load(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		len   int
+		err   string
+	}{
+		{
+			name:  "Providers API response",
+			input: `{"value": [{"namespace": "Microsoft.Foo", "resourceTypes": [{"resourceType": "foos"}, {"resourceType": "foos/bars"}]}]}`,
+			len:   2,
+		},
+		{
+			name:  "Single provider",
+			input: `{"namespace": "Microsoft.Foo", "resourceTypes": [{"resourceType": "foos"}]}`,
+			len:   1,
+		},
+		{
+			name:  "Missing namespace",
+			input: `[{"resourceTypes": [{"resourceType": "foos"}]}]`,
+			err:   "missing provider namespace",
+		},
+		{
+			name:  "Not JSON",
+			input: `foo`,
+			err:   "expect a JSON array or object",
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewTypeRegistry()
+			err := r.Load(strings.NewReader(tt.input))
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.len, r.Len())
+		})
+	}
+}
+
+func TestLoadTypeRegistryFiles(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "providers.json")
+	require.NoError(t, os.WriteFile(file, []byte(azProviderList), 0644))
+	r, err := LoadTypeRegistryFiles(file)
+	require.NoError(t, err)
+	info, ok := r.LookupType("MICROSOFT.NETWORK/VIRTUALNETWORKS")
+	require.True(t, ok)
+	require.Equal(t, "Microsoft.Network/virtualNetworks", info.TypeString())
+}
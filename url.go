@@ -0,0 +1,214 @@
+package armid
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Cloud represents an Azure cloud environment, which is identified by its ARM management endpoint.
+type Cloud int
+
+const (
+	// CloudUnknown indicates the endpoint is not a known ARM management endpoint, or there is no endpoint at all (e.g. a relative URL).
+	CloudUnknown Cloud = iota
+	// CloudPublic is the Azure public cloud.
+	CloudPublic
+	// CloudChina is the Azure China cloud.
+	CloudChina
+	// CloudUSGovernment is the Azure US Government cloud.
+	CloudUSGovernment
+)
+
+// Endpoints of the ARM management plane for the known clouds.
+const (
+	EndpointPublic       = "https://management.azure.com"
+	EndpointChina        = "https://management.chinacloudapi.cn"
+	EndpointUSGovernment = "https://management.usgovcloudapi.net"
+)
+
+var cloudHosts = map[string]Cloud{
+	"management.azure.com":              CloudPublic,
+	"management.chinacloudapi.cn":       CloudChina,
+	"management.usgovcloudapi.net":      CloudUSGovernment,
+	"management.core.windows.net":       CloudPublic,
+	"management.core.chinacloudapi.cn":  CloudChina,
+	"management.core.usgovcloudapi.net": CloudUSGovernment,
+}
+
+func (c Cloud) String() string {
+	switch c {
+	case CloudUnknown:
+		return "Unknown"
+	case CloudPublic:
+		return "AzurePublic"
+	case CloudChina:
+		return "AzureChina"
+	case CloudUSGovernment:
+		return "AzureUSGovernment"
+	default:
+		return fmt.Sprintf("Cloud(%d)", int(c))
+	}
+}
+
+// Endpoint returns the ARM management endpoint of the cloud. It is empty for CloudUnknown.
+func (c Cloud) Endpoint() string {
+	switch c {
+	case CloudPublic:
+		return EndpointPublic
+	case CloudChina:
+		return EndpointChina
+	case CloudUSGovernment:
+		return EndpointUSGovernment
+	default:
+		return ""
+	}
+}
+
+// CloudOfEndpoint returns the cloud of the given ARM management endpoint, which can be either a URL or a bare host name.
+func CloudOfEndpoint(endpoint string) Cloud {
+	host := endpoint
+	if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+		host = u.Hostname()
+	}
+	return cloudHosts[strings.ToLower(host)]
+}
+
+// ResourceURL is the result of parsing an ARM request URL.
+type ResourceURL struct {
+	// Id is the resource id addressed by the URL path.
+	Id ResourceId
+
+	// Endpoint is the scheme and host of the URL, e.g. "https://management.azure.com". It is empty for relative URLs.
+	Endpoint string
+
+	// Cloud is the cloud that the Endpoint belongs to.
+	Cloud Cloud
+
+	// APIVersion is the value of the "api-version" query parameter.
+	APIVersion string
+
+	// Query contains the query parameters other than the "api-version".
+	Query url.Values
+}
+
+// ParseResourceURL parses an absolute or relative ARM request URL, e.g.
+// "https://management.azure.com/subscriptions/0000/resourceGroups/rg1?api-version=2021-01-01".
+// The path segments are percent-decoded before being parsed by ParseResourceId. A *ParseError returned from parsing the path is
+// mapped back to the raw URL, i.e. its Input is the raw URL, and its Offset and Segment refer to the (escaped) segment in it.
+func ParseResourceURL(rawURL string) (*ResourceURL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &ResourceURL{}
+	if u.IsAbs() || u.Host != "" {
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, fmt.Errorf("unsupported URL scheme %q", u.Scheme)
+		}
+		if u.Host == "" {
+			return nil, fmt.Errorf("missing host in URL %q", rawURL)
+		}
+		out.Endpoint = u.Scheme + "://" + u.Host
+		out.Cloud = cloudHosts[strings.ToLower(u.Hostname())]
+	}
+
+	path := u.EscapedPath()
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("URL path %q should start with \"/\"", path)
+	}
+	segs := strings.Split(path[1:], "/")
+	for i, seg := range segs {
+		seg, err := url.PathUnescape(seg)
+		if err != nil {
+			return nil, fmt.Errorf("unescaping the %dth segment of URL path: %v", i+1, err)
+		}
+		if strings.Contains(seg, "/") {
+			return nil, fmt.Errorf("the %dth segment of URL path contains an escaped \"/\"", i+1)
+		}
+		segs[i] = seg
+	}
+	id, err := ParseResourceId("/" + strings.Join(segs, "/"))
+	if err != nil {
+		var perr *ParseError
+		if errors.As(err, &perr) {
+			return nil, urlParseError(rawURL, u.Host != "", perr)
+		}
+		return nil, err
+	}
+	out.Id = id
+
+	query := u.Query()
+	out.APIVersion = query.Get("api-version")
+	query.Del("api-version")
+	out.Query = query
+
+	return out, nil
+}
+
+// urlParseError maps the *ParseError of parsing the decoded URL path back to the raw URL.
+func urlParseError(rawURL string, hasHost bool, perr *ParseError) *ParseError {
+	start := 0
+	if hasHost {
+		start = len(rawURL)
+		if idx := strings.Index(rawURL, "//"); idx != -1 {
+			if pidx := strings.Index(rawURL[idx+2:], "/"); pidx != -1 {
+				start = idx + 2 + pidx
+			}
+		}
+	}
+	rawPath := rawURL[start:]
+	if idx := strings.IndexAny(rawPath, "?#"); idx != -1 {
+		rawPath = rawPath[:idx]
+	}
+
+	out := *perr
+	out.Input = rawURL
+	out.Offset = start
+	if rawPath == "" {
+		return &out
+	}
+	rawSegs := strings.Split(rawPath[1:], "/")
+	if perr.SegmentIndex >= 0 && perr.SegmentIndex < len(rawSegs) {
+		out.Offset = start + segmentOffset(rawSegs, perr.SegmentIndex)
+		out.Segment = rawSegs[perr.SegmentIndex]
+	}
+	return &out
+}
+
+// FormatResourceURL builds the ARM request URL of the resource id, with each path segment escaped.
+// The endpoint can be empty, which results into a relative URL. The api-version query parameter is omitted if apiVersion is empty.
+func FormatResourceURL(id ResourceId, endpoint, apiVersion string) (*url.URL, error) {
+	u := &url.URL{}
+	if endpoint != "" {
+		eu, err := url.Parse(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("parsing endpoint %q: %v", endpoint, err)
+		}
+		if eu.Scheme == "" || eu.Host == "" {
+			return nil, fmt.Errorf("endpoint %q should be an absolute URL", endpoint)
+		}
+		u.Scheme = eu.Scheme
+		u.Host = eu.Host
+	}
+
+	path := id.String()
+	u.Path = path
+	if path != "/" {
+		segs := strings.Split(path[1:], "/")
+		for i, seg := range segs {
+			segs[i] = url.PathEscape(seg)
+		}
+		u.RawPath = "/" + strings.Join(segs, "/")
+	}
+
+	if apiVersion != "" {
+		u.RawQuery = url.Values{"api-version": []string{apiVersion}}.Encode()
+	}
+	return u, nil
+}
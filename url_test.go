@@ -0,0 +1,183 @@
+package armid
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseResourceURL(t *testing.T) {
+	cases := []struct {
+		name   string
+		input  string
+		expect *ResourceURL
+		err    string
+	}{
+		{
+			name:  "Public cloud",
+			input: "https://management.azure.com/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Foo/foos/foo1?api-version=2021-01-01",
+			expect: &ResourceURL{
+				Id: &ScopedResourceId{
+					AttrParentScope: &ResourceGroup{SubscriptionId: "sub1", Name: "rg1"},
+					AttrProvider:    "Microsoft.Foo",
+					AttrTypes:       []string{"foos"},
+					AttrNames:       []string{"foo1"},
+				},
+				Endpoint:   "https://management.azure.com",
+				Cloud:      CloudPublic,
+				APIVersion: "2021-01-01",
+				Query:      url.Values{},
+			},
+		},
+		{
+			name:  "Sovereign cloud with extra query parameters",
+			input: "https://Management.ChinaCloudAPI.cn/subscriptions/sub1?api-version=2020-01-01&$expand=tags",
+			expect: &ResourceURL{
+				Id:         &SubscriptionId{Id: "sub1"},
+				Endpoint:   "https://Management.ChinaCloudAPI.cn",
+				Cloud:      CloudChina,
+				APIVersion: "2020-01-01",
+				Query:      url.Values{"$expand": []string{"tags"}},
+			},
+		},
+		{
+			name:  "Unknown host",
+			input: "http://localhost:8080/subscriptions/sub1",
+			expect: &ResourceURL{
+				Id:       &SubscriptionId{Id: "sub1"},
+				Endpoint: "http://localhost:8080",
+				Cloud:    CloudUnknown,
+				Query:    url.Values{},
+			},
+		},
+		{
+			name:  "Relative URL with percent-encoded segment and trailing slash",
+			input: "/subscriptions/sub1/resourceGroups/my%20rg/?api-version=2021-01-01",
+			expect: &ResourceURL{
+				Id:         &ResourceGroup{SubscriptionId: "sub1", Name: "my rg"},
+				APIVersion: "2021-01-01",
+				Query:      url.Values{},
+			},
+		},
+		{
+			name:  "Escaped slash",
+			input: "/subscriptions/sub1/resourceGroups/a%2Fb",
+			err:   `the 4th segment of URL path contains an escaped "/"`,
+		},
+		{
+			name:  "Unsupported scheme",
+			input: "ftp://management.azure.com/subscriptions/sub1",
+			err:   `unsupported URL scheme "ftp"`,
+		},
+		{
+			name:  "Invalid resource id",
+			input: "https://management.azure.com/subscriptions/sub1/providers/Microsoft.Foo/foos",
+			err:   `extending for RP Microsoft.Foo: missing resource type name after type foos`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := ParseResourceURL(tt.input)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, out)
+		})
+	}
+}
+
+func TestParseResourceURL_ParseError(t *testing.T) {
+	cases := []struct {
+		name    string
+		input   string
+		kind    ParseErrorKind
+		offset  int
+		segment string
+	}{
+		{
+			name:    "Absolute URL",
+			input:   "https://management.azure.com/subscriptions/my%20sub/resourceGroups?api-version=2021-01-01",
+			kind:    ParseErrorMissingResourceName,
+			offset:  52,
+			segment: "resourceGroups",
+		},
+		{
+			name:    "Relative URL",
+			input:   "/subscriptions/my%20sub/resourceGroups/r%20g/providers/Microsoft.Foo/foos",
+			kind:    ParseErrorMissingResourceName,
+			offset:  69,
+			segment: "foos",
+		},
+		{
+			name:    "Escaped offending segment",
+			input:   "https://management.azure.com/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Foo/foos/foo1/my%20type",
+			kind:    ParseErrorMissingResourceName,
+			offset:  101,
+			segment: "my%20type",
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseResourceURL(tt.input)
+			var perr *ParseError
+			require.True(t, errors.As(err, &perr), err)
+			require.Equal(t, tt.kind, perr.Kind)
+			require.Equal(t, tt.input, perr.Input)
+			require.Equal(t, tt.offset, perr.Offset)
+			require.Equal(t, tt.segment, perr.Segment)
+			require.Equal(t, tt.segment, tt.input[perr.Offset:perr.Offset+len(perr.Segment)])
+		})
+	}
+}
+
+func TestFormatResourceURL(t *testing.T) {
+	cases := []struct {
+		name       string
+		id         ResourceId
+		endpoint   string
+		apiVersion string
+		expect     string
+	}{
+		{
+			name:       "Absolute",
+			id:         &ResourceGroup{SubscriptionId: "sub1", Name: "rg1"},
+			endpoint:   EndpointPublic,
+			apiVersion: "2021-01-01",
+			expect:     "https://management.azure.com/subscriptions/sub1/resourceGroups/rg1?api-version=2021-01-01",
+		},
+		{
+			name: "Relative with escaping",
+			id: &ScopedResourceId{
+				AttrParentScope: &ResourceGroup{SubscriptionId: "sub1", Name: "my rg"},
+				AttrProvider:    "Microsoft.Foo",
+				AttrTypes:       []string{"foos"},
+				AttrNames:       []string{"a?b#c"},
+			},
+			expect: "/subscriptions/sub1/resourceGroups/my%20rg/providers/Microsoft.Foo/foos/a%3Fb%23c",
+		},
+		{
+			name:     "Tenant",
+			id:       &TenantId{},
+			endpoint: EndpointUSGovernment,
+			expect:   "https://management.usgovcloudapi.net/",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := FormatResourceURL(tt.id, tt.endpoint, tt.apiVersion)
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, u.String())
+
+			out, err := ParseResourceURL(u.String())
+			require.NoError(t, err)
+			require.True(t, tt.id.Equal(out.Id))
+			require.Equal(t, tt.apiVersion, out.APIVersion)
+		})
+	}
+}
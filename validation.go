@@ -0,0 +1,274 @@
+package armid
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NameRule is the naming rule of a resource type.
+// The zero value of each field means no restriction.
+type NameRule struct {
+	// MinLength is the minimum length of the name, in characters.
+	MinLength int
+
+	// MaxLength is the maximum length of the name, in characters.
+	MaxLength int
+
+	// Charset reports whether a character is allowed in the name.
+	Charset func(r rune) bool
+
+	// Start reports whether a character is allowed as the first character of the name.
+	Start func(r rune) bool
+
+	// End reports whether a character is allowed as the last character of the name.
+	End func(r rune) bool
+
+	// Reserved are the words that can't be used as the name, which are compared case-insensitively.
+	Reserved []string
+}
+
+// check checks the name against the rule, returning the reasons of the violations.
+func (rule NameRule) check(name string) []string {
+	var reasons []string
+	if n := utf8.RuneCountInString(name); (rule.MinLength != 0 && n < rule.MinLength) || (rule.MaxLength != 0 && n > rule.MaxLength) {
+		reasons = append(reasons, fmt.Sprintf("length %d is out of range [%d, %d]", n, rule.MinLength, rule.MaxLength))
+	}
+	if rule.Charset != nil {
+		for i, r := range name {
+			if !rule.Charset(r) {
+				reasons = append(reasons, fmt.Sprintf("invalid character %q at byte offset %d", r, i))
+				break
+			}
+		}
+	}
+	if name != "" {
+		if r, _ := utf8.DecodeRuneInString(name); rule.Start != nil && !rule.Start(r) {
+			reasons = append(reasons, fmt.Sprintf("invalid starting character %q", r))
+		}
+		if r, _ := utf8.DecodeLastRuneInString(name); rule.End != nil && !rule.End(r) {
+			reasons = append(reasons, fmt.Sprintf("invalid ending character %q", r))
+		}
+	}
+	for _, w := range rule.Reserved {
+		if strings.EqualFold(w, name) {
+			reasons = append(reasons, fmt.Sprintf("%q is a reserved word", name))
+			break
+		}
+	}
+	return reasons
+}
+
+// NameValidationError is a violation of the naming rule of a name in the resource id.
+type NameValidationError struct {
+	// Id is the resource id literal.
+	Id string
+
+	// TypeString is the resource type of the name, in the form of ResourceId.TypeString().
+	TypeString string
+
+	// Name is the offending name.
+	Name string
+
+	// SegmentIndex is the index of the name segment in Id, counting from the first segment following the leading "/".
+	SegmentIndex int
+
+	// Offset is the byte offset of the name segment in Id.
+	Offset int
+
+	// Reason describes the violation.
+	Reason string
+}
+
+func (e *NameValidationError) Error() string {
+	return fmt.Sprintf("invalid name %q of type %s (segment %d): %s", e.Name, e.TypeString, e.SegmentIndex, e.Reason)
+}
+
+// NameValidationErrors is a list of NameValidationError.
+type NameValidationErrors []*NameValidationError
+
+func (l NameValidationErrors) Error() string {
+	msgs := make([]string, 0, len(l))
+	for _, e := range l {
+		msgs = append(msgs, e.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// NameValidator validates the names of a resource id against the naming rules of their resource types.
+type NameValidator struct {
+	rules map[string]NameRule
+}
+
+// NewNameValidator creates a NameValidator with the builtin rules of the common resource types.
+func NewNameValidator() *NameValidator {
+	v := &NameValidator{rules: map[string]NameRule{}}
+	for typ, rule := range builtinNameRules {
+		v.Register(typ, rule)
+	}
+	return v
+}
+
+// Register registers the naming rule of the resource type, in the form of ResourceId.TypeString(), which overrides the existing one.
+func (v *NameValidator) Register(typeString string, rule NameRule) {
+	v.rules[strings.ToLower(typeString)] = rule
+}
+
+// Rule returns the naming rule of the resource type.
+func (v *NameValidator) Rule(typeString string) (NameRule, bool) {
+	rule, ok := v.rules[strings.ToLower(typeString)]
+	return rule, ok
+}
+
+// Validate validates each name of the resource id, including the ones of its parent scopes, against the naming rule of its resource type.
+// The names of the resource types without a registered rule are not validated.
+// The returned error is of type NameValidationErrors, or nil if there is no violation.
+func (v *NameValidator) Validate(id ResourceId) error {
+	var errs NameValidationErrors
+	idStr := id.String()
+	offset := 1
+	seg := 0
+	traverseScopes(id, func(scope ResourceId) {
+		var leadingSegs []string
+		switch scope := scope.(type) {
+		case *TenantId:
+			return
+		case *ManagementGroup:
+			leadingSegs = []string{"providers", scope.microsoftManagementLiteral()}
+		case *ScopedResourceId:
+			leadingSegs = []string{"providers", scope.AttrProvider}
+		}
+		for _, s := range leadingSegs {
+			offset += len(s) + 1
+			seg++
+		}
+		types, names := scope.Types(), scope.Names()
+		for i, name := range names {
+			offset += len(types[i]) + 1
+			seg++
+			typeString := strings.Join(append([]string{scope.Provider()}, types[:i+1]...), "/")
+			if rule, ok := v.Rule(typeString); ok {
+				for _, reason := range rule.check(name) {
+					errs = append(errs, &NameValidationError{
+						Id:           idStr,
+						TypeString:   typeString,
+						Name:         name,
+						SegmentIndex: seg,
+						Offset:       offset,
+						Reason:       reason,
+					})
+				}
+			}
+			offset += len(name) + 1
+			seg++
+		}
+	})
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+var defaultNameValidator = NewNameValidator()
+
+// ValidateNames validates the names of the resource id with the builtin naming rules. See NameValidator.Validate for details.
+func ValidateNames(id ResourceId) error {
+	return defaultNameValidator.Validate(id)
+}
+
+func isASCIILetterOrDigit(r rune) bool {
+	return ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9')
+}
+
+func isLowerASCIILetterOrDigit(r rune) bool {
+	return ('a' <= r && r <= 'z') || ('0' <= r && r <= '9')
+}
+
+func isASCIILetter(r rune) bool {
+	return ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z')
+}
+
+// runeIn returns a function that reports whether a character is either accepted by f, or one of the extra characters.
+func runeIn(f func(rune) bool, extra string) func(rune) bool {
+	return func(r rune) bool {
+		return f(r) || strings.ContainsRune(extra, r)
+	}
+}
+
+// reservedNames are the names that are reserved by Azure for the resources with public endpoints.
+var reservedNames = []string{"AccessControl", "Azure", "Microsoft", "Office", "Office365", "Windows", "Xbox"}
+
+// builtinNameRules are the naming rules of the common resource types, as documented in
+// https://learn.microsoft.com/azure/azure-resource-manager/management/resource-name-rules
+var builtinNameRules = map[string]NameRule{
+	"Microsoft.Resources/subscriptions/resourceGroups": {
+		MinLength: 1,
+		MaxLength: 90,
+		Charset: func(r rune) bool {
+			return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_-.()", r)
+		},
+		End: func(r rune) bool { return r != '.' },
+	},
+	"Microsoft.Storage/storageAccounts": {
+		MinLength: 3,
+		MaxLength: 24,
+		Charset:   isLowerASCIILetterOrDigit,
+	},
+	"Microsoft.KeyVault/vaults": {
+		MinLength: 3,
+		MaxLength: 24,
+		Charset:   runeIn(isASCIILetterOrDigit, "-"),
+		Start:     isASCIILetter,
+		End:       isASCIILetterOrDigit,
+	},
+	"Microsoft.Network/virtualNetworks": {
+		MinLength: 2,
+		MaxLength: 64,
+		Charset:   runeIn(isASCIILetterOrDigit, "_.-"),
+		Start:     isASCIILetterOrDigit,
+		End:       runeIn(isASCIILetterOrDigit, "_"),
+	},
+	"Microsoft.Network/virtualNetworks/subnets": {
+		MinLength: 1,
+		MaxLength: 80,
+		Charset:   runeIn(isASCIILetterOrDigit, "_.-"),
+		Start:     isASCIILetterOrDigit,
+		End:       runeIn(isASCIILetterOrDigit, "_"),
+	},
+	"Microsoft.Network/networkSecurityGroups": {
+		MinLength: 1,
+		MaxLength: 80,
+		Charset:   runeIn(isASCIILetterOrDigit, "_.-"),
+		Start:     isASCIILetterOrDigit,
+		End:       runeIn(isASCIILetterOrDigit, "_"),
+	},
+	"Microsoft.Network/publicIPAddresses": {
+		MinLength: 1,
+		MaxLength: 80,
+		Charset:   runeIn(isASCIILetterOrDigit, "_.-"),
+		Start:     isASCIILetterOrDigit,
+		End:       runeIn(isASCIILetterOrDigit, "_"),
+	},
+	"Microsoft.Compute/virtualMachines": {
+		MinLength: 1,
+		MaxLength: 64,
+		Charset:   runeIn(isASCIILetterOrDigit, ".-"),
+		Start:     isASCIILetterOrDigit,
+		End:       isASCIILetterOrDigit,
+	},
+	"Microsoft.ContainerRegistry/registries": {
+		MinLength: 5,
+		MaxLength: 50,
+		Charset:   isASCIILetterOrDigit,
+		Reserved:  reservedNames,
+	},
+	"Microsoft.Web/sites": {
+		MinLength: 2,
+		MaxLength: 60,
+		Charset:   runeIn(isASCIILetterOrDigit, "-"),
+		Start:     isASCIILetterOrDigit,
+		End:       isASCIILetterOrDigit,
+		Reserved:  reservedNames,
+	},
+}
@@ -0,0 +1,117 @@
+package armid
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateNames(t *testing.T) {
+	cases := []struct {
+		name   string
+		input  string
+		expect NameValidationErrors
+	}{
+		{
+			name:  "Valid",
+			input: "/subscriptions/sub1/resourceGroups/rg-1(dev)/providers/Microsoft.Storage/storageAccounts/mystorage01",
+		},
+		{
+			name:  "Unknown type is not validated",
+			input: "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Foo/foos/%%%",
+		},
+		{
+			name:  "Storage account",
+			input: "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Storage/storageAccounts/My_Storage",
+			expect: NameValidationErrors{
+				{
+					TypeString:   "Microsoft.Storage/storageAccounts",
+					Name:         "My_Storage",
+					SegmentIndex: 7,
+					Offset:       83,
+					Reason:       `invalid character 'M' at byte offset 0`,
+				},
+			},
+		},
+		{
+			name:  "Resource group and key vault",
+			input: "/subscriptions/sub1/resourceGroups/rg1./providers/Microsoft.KeyVault/vaults/1k",
+			expect: NameValidationErrors{
+				{
+					TypeString:   "Microsoft.Resources/subscriptions/resourceGroups",
+					Name:         "rg1.",
+					SegmentIndex: 3,
+					Offset:       35,
+					Reason:       `invalid ending character '.'`,
+				},
+				{
+					TypeString:   "Microsoft.KeyVault/vaults",
+					Name:         "1k",
+					SegmentIndex: 7,
+					Offset:       76,
+					Reason:       "length 2 is out of range [3, 24]",
+				},
+				{
+					TypeString:   "Microsoft.KeyVault/vaults",
+					Name:         "1k",
+					SegmentIndex: 7,
+					Offset:       76,
+					Reason:       `invalid starting character '1'`,
+				},
+			},
+		},
+		{
+			name:  "Child resource under management group scope",
+			input: "/providers/Microsoft.Management/managementGroups/mg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/-sub",
+			expect: NameValidationErrors{
+				{
+					TypeString:   "Microsoft.Network/virtualNetworks/subnets",
+					Name:         "-sub",
+					SegmentIndex: 9,
+					Offset:       111,
+					Reason:       `invalid starting character '-'`,
+				},
+			},
+		},
+		{
+			name:  "Reserved word",
+			input: "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Web/sites/azure",
+			expect: NameValidationErrors{
+				{
+					TypeString:   "Microsoft.Web/sites",
+					Name:         "azure",
+					SegmentIndex: 7,
+					Offset:       69,
+					Reason:       `"azure" is a reserved word`,
+				},
+			},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := ParseResourceId(tt.input)
+			require.NoError(t, err)
+			err = ValidateNames(id)
+			if tt.expect == nil {
+				require.NoError(t, err)
+				return
+			}
+			var errs NameValidationErrors
+			require.True(t, errors.As(err, &errs))
+			for _, e := range tt.expect {
+				e.Id = tt.input
+				require.Equal(t, e.Name, tt.input[e.Offset:e.Offset+len(e.Name)])
+			}
+			require.Equal(t, tt.expect, errs)
+		})
+	}
+}
+
+func TestNameValidator_Register(t *testing.T) {
+	v := NewNameValidator()
+	v.Register("microsoft.foo/foos", NameRule{MaxLength: 3})
+	id, err := ParseResourceId("/subscriptions/sub1/providers/Microsoft.Foo/foos/foo1")
+	require.NoError(t, err)
+	require.EqualError(t, v.Validate(id), `invalid name "foo1" of type Microsoft.Foo/foos (segment 5): length 4 is out of range [0, 3]`)
+}